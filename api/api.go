@@ -11,29 +11,128 @@ import (
 // If ok is true, there is more to traverse. If ok is false, the traversal is finished.
 type TraverseFunc func(prev image.Point, bounds image.Rectangle) (next image.Point, ok bool)
 
+// Sonifier takes a color and returns a beep.Streamer sonifying that color.
+// Any state that should be kept between calls to Sonify belongs to the Sonifier itself.
+// Sonifiers that have something to report to observers, such as UIs, can implement Observable.
+type Sonifier interface {
+	Sonify(color.Color, beep.SampleRate) beep.Streamer
+}
+
+// ImageSonifier is a Sonifier that sonifies a pixel in the context of its image, rather than
+// by its color alone. The PixelSounder and players call SonifyAt instead of Sonify if it is implemented.
+type ImageSonifier interface {
+	Sonifier
+	SonifyAt(img image.Image, p image.Point, sr beep.SampleRate) beep.Streamer
+}
+
 // SonifyFunc is a function that takes a color and returns a beep.Streamer sonifying that color.
-// It also takes an returns an interface for any state that should be passed between calls to
-// the SonifyFunc.
-type SonifyFunc func(color.Color, beep.SampleRate, interface{}) (beep.Streamer, interface{})
+// It implements Sonifier for sonifications that don't keep any state.
+type SonifyFunc func(color.Color, beep.SampleRate) beep.Streamer
+
+// Sonify calls the SonifyFunc.
+func (f SonifyFunc) Sonify(c color.Color, sr beep.SampleRate) beep.Streamer {
+	return f(c, sr)
+}
 
 // PixelSound is an interface that describes how an image is traversed and sonified.
 type PixelSound interface {
-	Traverse(image.Point, image.Rectangle) (image.Point, bool)
-	Sonify(color.Color, beep.SampleRate, interface{}) (beep.Streamer, interface{})
+	Traverser
+	Sonify(color.Color, beep.SampleRate) beep.Streamer
+}
+
+// Cursor is a traversal of an image by a PixelSound from a start pixel, which can be played
+// alongside other Cursors.
+type Cursor struct {
+	PS    PixelSound
+	Start image.Point
 }
 
 // PixelSounder is a struct that implements the PixelSound interface.
+// The image is traversed by Tr if set, otherwise by T. If S is an EventHandler and the
+// Traverser is Observable, S handles the Events of the Traverser.
 type PixelSounder struct {
-	T TraverseFunc
-	S SonifyFunc
+	T           TraverseFunc
+	Tr          Traverser
+	S           Sonifier
+	t           Traverser // Traverser of the current traversal
+	unsubscribe func()    // Unsubscribes S from the Traverser of the current traversal
+}
+
+// Reset starts a new traversal with Tr, or with T if Tr isn't set.
+func (ps *PixelSounder) Reset(bounds image.Rectangle, img image.Image, start image.Point) {
+	ps.t = ps.Tr
+	if ps.t == nil {
+		ps.t = NewFuncTraverser(ps.T)
+	}
+
+	if ps.unsubscribe != nil {
+		ps.unsubscribe()
+		ps.unsubscribe = nil
+	}
+	if o, ok := ps.t.(Observable); ok {
+		if h, ok := ps.S.(EventHandler); ok {
+			ps.unsubscribe = o.Subscribe(h.HandleEvent)
+		}
+	}
+
+	ps.t.Reset(bounds, img, start)
+}
+
+// ColorAt returns the color of the pixel at p, as sampled by the Traverser if it is a ColorSampler.
+func (ps *PixelSounder) ColorAt(img image.Image, p image.Point) color.Color {
+	if cs, ok := ps.traverser().(ColorSampler); ok {
+		return cs.ColorAt(img, p)
+	}
+	return img.At(p.X, p.Y)
+}
+
+// traverser returns the Traverser of the current traversal, or Tr before the first one.
+func (ps *PixelSounder) traverser() Traverser {
+	if ps.t != nil {
+		return ps.t
+	}
+	return ps.Tr
+}
+
+// ColorAt returns the color of the pixel at p to sonify with a PixelSound, as sampled by the
+// PixelSound if it is a ColorSampler.
+func ColorAt(ps PixelSound, img image.Image, p image.Point) color.Color {
+	if cs, ok := ps.(ColorSampler); ok {
+		return cs.ColorAt(img, p)
+	}
+	return img.At(p.X, p.Y)
+}
+
+// SonifyAt returns a Streamer sonifying the pixel at p with a PixelSound, with SonifyAt if it is
+// an ImageSonifier, otherwise with Sonify of the color returned by ColorAt.
+func SonifyAt(ps PixelSound, img image.Image, p image.Point, sr beep.SampleRate) beep.Streamer {
+	if is, ok := ps.(ImageSonifier); ok {
+		return is.SonifyAt(img, p, sr)
+	}
+	return ps.Sonify(ColorAt(ps, img, p), sr)
+}
+
+// Next returns the next pixel of the current traversal.
+func (ps *PixelSounder) Next() (next image.Point, ok bool) {
+	if ps.t == nil {
+		return next, false
+	}
+	return ps.t.Next()
 }
 
-// Traverse calls a TraverseFunc.
-func (ps *PixelSounder) Traverse(prev image.Point, bounds image.Rectangle) (next image.Point, ok bool) {
-	return ps.T(prev, bounds)
+// Sonify calls a Sonifier.
+func (ps *PixelSounder) Sonify(c color.Color, sr beep.SampleRate) beep.Streamer {
+	return ps.S.Sonify(c, sr)
 }
 
-// Sonify calls a SonifyFunc.
-func (ps *PixelSounder) Sonify(c color.Color, sr beep.SampleRate, state interface{}) (beep.Streamer, interface{}) {
-	return ps.S(c, sr, state)
+// SonifyAt calls the Sonifier with the pixel at p if it is an ImageSonifier, otherwise with its color.
+// If the Traverser is a CellSampler, an ImageSonifier is called with the image of cells and the cell of p.
+func (ps *PixelSounder) SonifyAt(img image.Image, p image.Point, sr beep.SampleRate) beep.Streamer {
+	if is, ok := ps.S.(ImageSonifier); ok {
+		if cs, ok := ps.traverser().(CellSampler); ok {
+			img, p = cs.Cell(img, p)
+		}
+		return is.SonifyAt(img, p, sr)
+	}
+	return ps.S.Sonify(ps.ColorAt(img, p), sr)
 }
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"image/color"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// EventKind describes what an Event reports.
+type EventKind int
+
+const (
+	// ProgressEvent reports how far through its source a sonification is, from 0 to 1.
+	ProgressEvent EventKind = iota
+	// RegionEvent reports that a traversal is entering a new region of the image.
+	RegionEvent
+)
+
+// Event is something reported to observers, such as UIs.
+type Event struct {
+	Kind     EventKind
+	Progress float64     // Set for ProgressEvent
+	Region   int         // Index of the region, set for RegionEvent
+	Color    color.Color // Color of the region, set for RegionEvent
+}
+
+// Listener is a function that is called with Events.
+type Listener func(Event)
+
+// EventHandler is implemented by Sonifiers that react to the Events of the Traverser they
+// sonify with, such as changing timbre per region.
+type EventHandler interface {
+	HandleEvent(Event)
+}
+
+// Observable is implemented by anything that reports Events.
+type Observable interface {
+	// Subscribe registers a Listener to be called with every Event.
+	// Returns a function that when called unsubscribes the Listener.
+	Subscribe(Listener) func()
+}
+
+// Emitter implements Observable, and can be embedded to report Events.
+// The zero value is ready to use.
+type Emitter struct {
+	mu        sync.RWMutex
+	listeners map[string]Listener
+}
+
+// Subscribe registers a Listener to be called with every emitted Event.
+// Returns a function that when called unsubscribes the Listener.
+func (e *Emitter) Subscribe(l Listener) func() {
+	id := uuid.New().String()
+	e.mu.Lock()
+	if e.listeners == nil {
+		e.listeners = map[string]Listener{}
+	}
+	e.listeners[id] = l
+	e.mu.Unlock()
+
+	return func() {
+		e.mu.Lock()
+		delete(e.listeners, id)
+		e.mu.Unlock()
+	}
+}
+
+// Emit calls all subscribed Listeners with an Event. Listeners may subscribe or unsubscribe
+// while being called, taking effect from the next Event.
+func (e *Emitter) Emit(ev Event) {
+	e.mu.RLock()
+	listeners := make([]Listener, 0, len(e.listeners))
+	for _, l := range e.listeners {
+		listeners = append(listeners, l)
+	}
+	e.mu.RUnlock()
+	for _, l := range listeners {
+		l(ev)
+	}
+}
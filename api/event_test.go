@@ -0,0 +1,32 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEmitterUnsubscribeWhileEmitting(t *testing.T) {
+	var e Emitter
+	calls := 0
+	var unsubscribe func()
+	unsubscribe = e.Subscribe(func(Event) {
+		calls++
+		unsubscribe()
+	})
+
+	// A Listener unsubscribing itself is called once and doesn't block Emit
+	done := make(chan struct{})
+	go func() {
+		e.Emit(Event{Kind: ProgressEvent, Progress: 0.5})
+		e.Emit(Event{Kind: ProgressEvent, Progress: 1})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Emit blocked on a Listener unsubscribing")
+	}
+	if calls != 1 {
+		t.Errorf("Listener was called %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ParamKind is the type of a parameter's value.
+type ParamKind int
+
+const (
+	FloatParam  ParamKind = iota // Value is a float64
+	IntParam                     // Value is an int
+	BoolParam                    // Value is a bool
+	StringParam                  // Value is a string
+	AudioParam                   // Value is an Audio
+)
+
+// String returns the name of the ParamKind.
+func (k ParamKind) String() string {
+	switch k {
+	case FloatParam:
+		return "float"
+	case IntParam:
+		return "int"
+	case BoolParam:
+		return "bool"
+	case StringParam:
+		return "string"
+	case AudioParam:
+		return "audio"
+	}
+	return fmt.Sprintf("ParamKind(%d)", int(k))
+}
+
+// Audio is the value of an AudioParam, an audio buffer formatted with an extension
+// such as "mp3" or "wav".
+type Audio struct {
+	R   io.ReadCloser
+	Ext string
+}
+
+// Param describes a parameter of a traversal or sonification.
+type Param struct {
+	Name        string
+	Description string
+	Kind        ParamKind
+	Default     interface{} // Default value, nil for an AudioParam
+	Min, Max    float64     // Range of a FloatParam or IntParam, unbounded if equal
+	Choices     []string    // Allowed values of a StringParam, any value if empty
+}
+
+// Parse parses a string into a value of the Param's kind.
+// The string of an AudioParam is the path of an audio file, which is opened.
+func (p Param) Parse(s string) (interface{}, error) {
+	var v interface{}
+	var err error
+	switch p.Kind {
+	case FloatParam:
+		v, err = strconv.ParseFloat(s, 64)
+	case IntParam:
+		v, err = strconv.Atoi(s)
+	case BoolParam:
+		v, err = strconv.ParseBool(s)
+	case StringParam:
+		v = s
+	case AudioParam:
+		var f *os.File
+		if f, err = os.Open(s); err == nil {
+			v = Audio{
+				R:   f,
+				Ext: strings.ToLower(strings.TrimPrefix(filepath.Ext(s), ".")),
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q for %s: %w", s, p.Name, err)
+	}
+	return v, p.Validate(v)
+}
+
+// Validate checks that a value is of the Param's kind, and within its range or choices.
+func (p Param) Validate(v interface{}) error {
+	var f float64
+	switch p.Kind {
+	case FloatParam:
+		x, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("%s must be a float, got %T", p.Name, v)
+		}
+		f = x
+	case IntParam:
+		x, ok := v.(int)
+		if !ok {
+			return fmt.Errorf("%s must be an int, got %T", p.Name, v)
+		}
+		f = float64(x)
+	case BoolParam:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s must be a bool, got %T", p.Name, v)
+		}
+		return nil
+	case StringParam:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string, got %T", p.Name, v)
+		}
+		if len(p.Choices) == 0 {
+			return nil
+		}
+		for _, c := range p.Choices {
+			if s == c {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s must be one of %s, got %q", p.Name, strings.Join(p.Choices, ", "), s)
+	case AudioParam:
+		if _, ok := v.(Audio); !ok {
+			return fmt.Errorf("%s must be audio, got %T", p.Name, v)
+		}
+		return nil
+	}
+	if p.Min != p.Max && (f < p.Min || f > p.Max) {
+		return fmt.Errorf("%s must be between %v and %v, got %v", p.Name, p.Min, p.Max, f)
+	}
+	return nil
+}
+
+// Schema describes all of the parameters of a traversal or sonification.
+type Schema []Param
+
+// Lookup returns the Param with the provided name.
+func (s Schema) Lookup(name string) (Param, bool) {
+	for _, p := range s {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Param{}, false
+}
+
+// Defaults returns Params holding the default value of every Param.
+func (s Schema) Defaults() Params {
+	params := Params{}
+	for _, p := range s {
+		if p.Default != nil {
+			params[p.Name] = p.Default
+		}
+	}
+	return params
+}
+
+// Parse returns Params holding values parsed from strings by name, and the default value
+// of every Param without one. Audio is only opened once every other value is valid, and is
+// closed again if any of it can't be opened.
+func (s Schema) Parse(values map[string]string) (Params, error) {
+	params := s.Defaults()
+	var audio []Param
+	for name, value := range values {
+		p, ok := s.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown parameter %s", name)
+		}
+		if p.Kind == AudioParam {
+			audio = append(audio, p)
+			continue
+		}
+		v, err := p.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		params[name] = v
+	}
+	for _, p := range audio {
+		v, err := p.Parse(values[p.Name])
+		if err != nil {
+			params.Close()
+			return nil, err
+		}
+		params[p.Name] = v
+	}
+	return params, nil
+}
+
+// Params holds the values of parameters by name.
+// Getters return the zero value for parameters that aren't set or are of another kind.
+type Params map[string]interface{}
+
+// Float returns the value of a FloatParam.
+func (p Params) Float(name string) float64 {
+	v, _ := p[name].(float64)
+	return v
+}
+
+// Int returns the value of an IntParam.
+func (p Params) Int(name string) int {
+	v, _ := p[name].(int)
+	return v
+}
+
+// Bool returns the value of a BoolParam.
+func (p Params) Bool(name string) bool {
+	v, _ := p[name].(bool)
+	return v
+}
+
+// String returns the value of a StringParam.
+func (p Params) String(name string) string {
+	v, _ := p[name].(string)
+	return v
+}
+
+// Audio returns the value of an AudioParam.
+func (p Params) Audio(name string) Audio {
+	v, _ := p[name].(Audio)
+	return v
+}
+
+// Close closes the audio of every AudioParam.
+func (p Params) Close() error {
+	var err error
+	for _, v := range p {
+		if a, ok := v.(Audio); ok && a.R != nil {
+			if cerr := a.R.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	}
+	return err
+}
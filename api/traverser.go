@@ -0,0 +1,74 @@
+package api
+
+import (
+	"image"
+	"image/color"
+)
+
+// Traverser traverses an image, keeping any state it needs between steps, such as the pixels
+// already visited.
+type Traverser interface {
+	// Reset starts a new traversal of the pixels within bounds of an image, from the pixel at start.
+	// Traversers with an order of their own, such as sorting pixels, may start elsewhere.
+	Reset(bounds image.Rectangle, img image.Image, start image.Point)
+	// Next returns the next pixel of the traversal, starting with its first pixel after Reset.
+	// If ok is false, the traversal is finished.
+	Next() (next image.Point, ok bool)
+}
+
+// funcTraverser is a Traverser calling a TraverseFunc.
+type funcTraverser struct {
+	f       TraverseFunc
+	bounds  image.Rectangle
+	loc     image.Point
+	started bool // If set, the start pixel has been returned
+	done    bool
+}
+
+// NewFuncTraverser returns a Traverser that starts from the start pixel, then calls a
+// TraverseFunc with the previous pixel.
+func NewFuncTraverser(f TraverseFunc) Traverser {
+	return &funcTraverser{f: f}
+}
+
+// Reset starts a new traversal. A traversal of empty bounds, or from a pixel outside bounds,
+// is finished at once.
+func (t *funcTraverser) Reset(bounds image.Rectangle, img image.Image, start image.Point) {
+	t.bounds = bounds
+	t.loc = start
+	t.started = false
+	t.done = bounds.Empty() || !start.In(bounds)
+}
+
+// Next returns the start pixel, then calls the TraverseFunc, which returns the final pixel
+// along with ok set to false. If the TraverseFunc finishes at the previous pixel, it isn't
+// returned again.
+func (t *funcTraverser) Next() (image.Point, bool) {
+	if t.done {
+		return t.loc, false
+	}
+	if !t.started {
+		t.started = true
+		return t.loc, true
+	}
+	next, ok := t.f(t.loc, t.bounds)
+	if !ok && next == t.loc {
+		t.done = true
+		return t.loc, false
+	}
+	t.loc, t.done = next, !ok
+	return t.loc, true
+}
+
+// ColorSampler is implemented by Traversers that choose the color sonified for the pixels they
+// visit, such as the average color of a block of pixels.
+type ColorSampler interface {
+	ColorAt(img image.Image, p image.Point) color.Color
+}
+
+// CellSampler is implemented by Traversers that traverse cells of pixels rather than pixels,
+// such as blocks of pixels, so that ImageSonifiers sonify the image of cells they traverse.
+type CellSampler interface {
+	// Cell returns the image with a pixel for every cell of img, and the cell of the pixel at p.
+	Cell(img image.Image, p image.Point) (image.Image, image.Point)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+// rows traverses bounds row by row, returning the final pixel with ok set to false, and the
+// previous pixel if it is already the final pixel.
+func rows(prev image.Point, bounds image.Rectangle) (image.Point, bool) {
+	last := bounds.Max.Sub(image.Point{1, 1})
+	switch {
+	case prev == last:
+		return prev, false
+	case prev.X < last.X:
+		prev.X++
+	default:
+		prev = image.Point{bounds.Min.X, prev.Y + 1}
+	}
+	return prev, prev != last
+}
+
+func TestFuncTraverser(t *testing.T) {
+	tests := []struct {
+		name   string
+		bounds image.Rectangle
+		start  image.Point
+		want   []image.Point
+	}{
+		{"whole", image.Rect(0, 0, 2, 2), image.Point{}, []image.Point{{0, 0}, {1, 0}, {0, 1}, {1, 1}}},
+		{"single pixel", image.Rect(0, 0, 1, 1), image.Point{}, []image.Point{{0, 0}}},
+		{"from last pixel", image.Rect(0, 0, 2, 2), image.Point{1, 1}, []image.Point{{1, 1}}},
+		{"empty", image.Rect(5, 5, 5, 5), image.Point{5, 5}, nil},
+		{"from outside", image.Rect(5, 5, 7, 7), image.Point{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewFuncTraverser(rows)
+			tr.Reset(tt.bounds, nil, tt.start)
+			var got []image.Point
+			for p, ok := tr.Next(); ok; p, ok = tr.Next() {
+				got = append(got, p)
+				if len(got) > len(tt.want) {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("traversal of %v from %v is %v, want %v", tt.bounds, tt.start, got, tt.want)
+			}
+			if _, ok := tr.Next(); ok {
+				t.Error("Next after the traversal finished returned ok")
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"errors"
+	"io"
+)
+
+// writeSeekBuffer is an in-memory io.WriteSeeker.
+type writeSeekBuffer struct {
+	buf []byte
+	pos int
+}
+
+// Write writes p at the current position, growing the buffer as needed.
+func (b *writeSeekBuffer) Write(p []byte) (n int, err error) {
+	if end := b.pos + len(p); end > len(b.buf) {
+		b.buf = append(b.buf, make([]byte, end-len(b.buf))...)
+	}
+	n = copy(b.buf[b.pos:], p)
+	b.pos += n
+	return n, nil
+}
+
+// Seek sets the position for the next Write.
+func (b *writeSeekBuffer) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = int64(b.pos) + offset
+	case io.SeekEnd:
+		pos = int64(len(b.buf)) + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("negative position")
+	}
+	b.pos = int(pos)
+	return pos, nil
+}
+
+// Bytes returns everything written to the buffer.
+func (b *writeSeekBuffer) Bytes() []byte {
+	return b.buf
+}
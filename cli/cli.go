@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Command is a pixelsound subcommand, e.g. `pixelsound render`.
+type Command struct {
+	Name    string                    // Name used to invoke the command
+	Summary string                    // One line description of the command
+	Run     func(args []string) error // Runs the command with the args following its name
+}
+
+// commands contains all of the registered commands.
+var commands = map[string]*Command{}
+
+// Register adds a command to the CLI.
+func Register(cmd *Command) {
+	commands[cmd.Name] = cmd
+}
+
+// Lookup returns the command with the provided name.
+func Lookup(name string) (*Command, bool) {
+	cmd, ok := commands[name]
+	return cmd, ok
+}
+
+// Usage writes a summary of all registered commands to w.
+func Usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "usage: pixelsound <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-8s %s\n", name, commands[name].Summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "run `pixelsound <command> -h` for the flags of a command.")
+}
+
+func init() {
+	Register(&Command{
+		Name:    "render",
+		Summary: "render a traversal of an image to an audio file without playing it",
+		Run:     runRender,
+	})
+	Register(&Command{
+		Name:    "list",
+		Summary: "list the available traversal and sonification functions",
+		Run:     runList,
+	})
+	Register(&Command{
+		Name:    "info",
+		Summary: "describe a traversal or sonification function",
+		Run:     runInfo,
+	})
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/sonification"
+	"github.com/rytrose/pixelsound/traversal"
+)
+
+// runList prints the names of all traversal and sonification functions.
+func runList(args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	traversals := flags.Bool("t", false, "only list traversal functions")
+	sonifications := flags.Bool("s", false, "only list sonification functions")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	all := !*traversals && !*sonifications
+
+	if all || *traversals {
+		if all {
+			fmt.Println("traversal functions:")
+		}
+		for _, name := range traversal.Names() {
+			t, _ := traversal.Lookup(name)
+			printName(name, t.Description, all)
+		}
+	}
+	if all || *sonifications {
+		if all {
+			fmt.Println("sonification functions:")
+		}
+		for _, name := range sonification.Names() {
+			s, _ := sonification.Lookup(name)
+			printName(name, s.Description, all)
+		}
+	}
+	return nil
+}
+
+// printName prints a name and description, indented if it is listed under a heading.
+func printName(name, description string, indent bool) {
+	if indent {
+		fmt.Printf("  %-16s %s\n", name, description)
+	} else {
+		fmt.Printf("%-16s %s\n", name, description)
+	}
+}
+
+// runInfo describes the traversal and/or sonification function with the provided name.
+func runInfo(args []string) error {
+	flags := flag.NewFlagSet("info", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pixelsound info <name>")
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected exactly one name, got %d", flags.NArg())
+	}
+	name := flags.Arg(0)
+
+	found := false
+	if t, ok := traversal.Lookup(name); ok {
+		found = true
+		printInfo(name, "traversal function", t.Description, t.Params)
+	}
+	if s, ok := sonification.Lookup(name); ok {
+		found = true
+		printInfo(name, "sonification function", s.Description, s.Params)
+	}
+	if !found {
+		return fmt.Errorf("no traversal or sonification function named %s", name)
+	}
+	return nil
+}
+
+// printInfo prints the description and parameters of a traversal or sonification function.
+func printInfo(name, kind, description string, params api.Schema) {
+	fmt.Printf("%s: %s\n", name, kind)
+	fmt.Printf("  %s\n", description)
+	if len(params) == 0 {
+		return
+	}
+	fmt.Println("parameters:")
+	for _, p := range params {
+		fmt.Printf("  %s (%s): %s\n", p.Name, p.Kind, p.Description)
+		var details []string
+		if p.Default != nil {
+			details = append(details, fmt.Sprintf("default %v", p.Default))
+		}
+		if p.Min != p.Max {
+			details = append(details, fmt.Sprintf("between %v and %v", p.Min, p.Max))
+		}
+		if len(p.Choices) > 0 {
+			details = append(details, "one of "+strings.Join(p.Choices, ", "))
+		}
+		if len(details) > 0 {
+			fmt.Printf("    %s\n", strings.Join(details, "; "))
+		}
+	}
+}
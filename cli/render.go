@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/faiface/beep"
+	"github.com/nfnt/resize"
+	"github.com/rytrose/pixelsound/config"
+	"github.com/rytrose/pixelsound/render"
+	"github.com/rytrose/pixelsound/ui"
+)
+
+// runRender renders a traversal of an image, or the image as a spectrogram, to an audio file, or stdout.
+func runRender(args []string) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	imageFilename := flags.String("im", "images/me.png", "image to pixelsound")
+	mode := flags.String("mode", "traversal", "how to play the image, traversal to sonify its pixels one at a time or spectrogram to play it as a spectrogram")
+	inputAudioFilename := flags.String("audio", "", "audio file to use for pixelsound (if needed)")
+	output := flags.String("o", "", "file to write audio to, or - for stdout (defaults to the image name with the format's extension)")
+	formatName := flags.String("format", "", "audio format to write, wav or flac (defaults to the extension of -o, or wav)")
+	traverseFunc := flags.String("t", "TtoBLtoR", "traversal function to use")
+	sonifyFunc := flags.String("s", "SineColor", "sonification function to use")
+	traverseParams := config.ParamValues{}
+	flags.Var(traverseParams, "tp", "traversal function parameter as name=value, may be repeated")
+	sonifyParams := config.ParamValues{}
+	flags.Var(sonifyParams, "sp", "sonification function parameter as name=value, may be repeated")
+	sampleRate := flags.Int("sr", 44100, "sample rate of the rendered audio")
+	width := flags.Uint("width", 100, "width to resize the image to before rendering, or 0 to keep the original size")
+	startX := flags.Int("x", 0, "x coordinate of the pixel to start the traversal from, without -layout")
+	startY := flags.Int("y", 0, "y coordinate of the pixel to start the traversal from, without -layout")
+	maxPixels := flags.Int("max-pixels", 0, "maximum number of pixels to render, or 0 for no limit")
+	maxDuration := flags.Duration("max-duration", 0, "maximum duration of audio to render, or 0 for no limit")
+	sampling := config.SamplingFlags(flags)
+	cursors := config.CursorFlags(flags)
+	spec := SpectrogramFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *mode != "traversal" && *mode != "spectrogram" {
+		return fmt.Errorf("no render mode named %s", *mode)
+	}
+
+	// Determine the output format
+	if *formatName == "" {
+		*formatName = "wav"
+		if ext := filepath.Ext(*output); *output != "-" && ext != "" {
+			*formatName = ext
+		}
+	}
+	format, err := render.FormatFromExt(*formatName)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		base := strings.TrimSuffix(filepath.Base(*imageFilename), filepath.Ext(*imageFilename))
+		*output = base + "." + strings.TrimPrefix(strings.ToLower(*formatName), ".")
+	}
+
+	// Load image
+	im, _, err := ui.LoadImageFromFile(*imageFilename)
+	if err != nil {
+		return fmt.Errorf("unable to load image %s: %w", *imageFilename, err)
+	}
+	if *width > 0 {
+		im = resize.Resize(*width, 0, im, resize.NearestNeighbor)
+	}
+
+	// Play the image as a spectrogram, or traverse it with a PixelSound for each cursor
+	sr := beep.SampleRate(*sampleRate)
+	var renderTo func(w io.WriteSeeker) error
+	if *mode == "spectrogram" {
+		renderTo = func(w io.WriteSeeker) error {
+			return spec.Render(w, format, im, sr)
+		}
+	} else {
+		var opts []render.RenderOpt
+		if *maxPixels > 0 {
+			opts = append(opts, render.WithMaxPixels(*maxPixels))
+		}
+		if *maxDuration > 0 {
+			opts = append(opts, render.WithMaxDuration(*maxDuration))
+		}
+		start := image.Point{*startX, *startY}
+		if !start.In(im.Bounds()) {
+			return fmt.Errorf("start pixel %s is outside of the image bounds %s", start, im.Bounds())
+		}
+		cs, err := config.NewCursors(*traverseFunc, traverseParams, *sonifyFunc, sonifyParams, *inputAudioFilename, *sampling, *cursors, im.Bounds(), start, sr)
+		if err != nil {
+			return err
+		}
+		renderTo = func(w io.WriteSeeker) error {
+			return render.RenderCursors(w, format, im, cs, sr, opts...)
+		}
+	}
+
+	// Render to stdout, which can't seek, through an in-memory buffer
+	if *output == "-" {
+		buf := &writeSeekBuffer{}
+		if err := renderTo(buf); err != nil {
+			return err
+		}
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+
+	out, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := renderTo(out); err != nil {
+		return err
+	}
+	return out.Close()
+}
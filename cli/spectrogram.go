@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/rytrose/pixelsound/spectrogram"
+)
+
+// SpectrogramFlags defines the flags configuring how an image is played as a spectrogram on a
+// FlagSet, returning the Spectrogram they configure once the flags are parsed.
+func SpectrogramFlags(flags *flag.FlagSet) *spectrogram.Spectrogram {
+	s := &spectrogram.Spectrogram{}
+	flags.Var((*scaleValue)(&s.Scale), "scale", "frequency scale of the height of the spectrogram, one of "+strings.Join(spectrogram.ScaleNames(), ", "))
+	flags.Float64Var(&s.MinFreq, "min-freq", 20, "frequency of the bottom of the spectrogram in Hz")
+	flags.Float64Var(&s.MaxFreq, "max-freq", 0, "frequency of the top of the spectrogram in Hz, or 0 for half the sample rate")
+	flags.DurationVar(&s.Duration, "duration", 5*time.Second, "duration of the spectrogram")
+	flags.IntVar(&s.FFTSize, "fft-size", 2048, "number of samples in each frame of the spectrogram, a power of two")
+	flags.IntVar(&s.Iterations, "iterations", 32, "iterations of Griffin-Lim phase reconstruction, or 0 for random phases")
+	flags.Int64Var(&s.Seed, "seed", 0, "seed of the random phases of the spectrogram")
+	return s
+}
+
+// scaleValue is a flag.Value for a spectrogram.Scale.
+type scaleValue spectrogram.Scale
+
+// String returns the name of the Scale.
+func (s *scaleValue) String() string {
+	if s == nil {
+		return spectrogram.Linear.String()
+	}
+	return spectrogram.Scale(*s).String()
+}
+
+// Set parses the name of a Scale.
+func (s *scaleValue) Set(name string) error {
+	scale, err := spectrogram.ParseScale(name)
+	if err != nil {
+		return err
+	}
+	*s = scaleValue(scale)
+	return nil
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"flag"
+	"image"
+
+	"github.com/faiface/beep"
+	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/traversal"
+)
+
+// Cursors configures multiple cursors traversing parts of an image at once.
+type Cursors struct {
+	Layout string // Name of the traversal.Layout of the cursors, or empty for a single cursor
+	N      int    // Number of cursors of the rows and columns layouts, or 0 for one per row or column of pixels
+}
+
+// CursorFlags defines the -layout and -cursors flags on a FlagSet, returning the Cursors they
+// configure once the flags are parsed.
+func CursorFlags(flags *flag.FlagSet) *Cursors {
+	c := &Cursors{}
+	flags.StringVar(&c.Layout, "layout", "", "traverse parts of the image with a cursor each at once, split into rows, columns or quadrants")
+	flags.IntVar(&c.N, "cursors", 4, "number of cursors of the rows and columns layouts, or 0 for one per row or column of pixels")
+	return c
+}
+
+// NewCursors instantiates a cursor for each part of an image with the provided bounds, as
+// configured by cursors. Each cursor has its own instances of the traversal and sonification,
+// as in NewPixelSound, and starts from the top left of its part. A single cursor starts from start.
+// It returns an error if the region of interest of sampling is outside of bounds.
+func NewCursors(traverseFunc string, traverseParams ParamValues, sonifyFunc string, sonifyParams ParamValues, audioFilename string, sampling traversal.Sampling, cursors Cursors, bounds image.Rectangle, start image.Point, sr beep.SampleRate) ([]api.Cursor, error) {
+	if err := CheckSampling(sampling, bounds); err != nil {
+		return nil, err
+	}
+	if cursors.Layout == "" {
+		ps, err := NewPixelSound(traverseFunc, traverseParams, sonifyFunc, sonifyParams, audioFilename, sampling, sr)
+		if err != nil {
+			return nil, err
+		}
+		return []api.Cursor{{PS: ps, Start: sampling.Snap(bounds, start)}}, nil
+	}
+
+	layout, err := traversal.ParseLayout(cursors.Layout)
+	if err != nil {
+		return nil, err
+	}
+	var cs []api.Cursor
+	for _, part := range layout.Split(sampling.Bounds(bounds), cursors.N) {
+		s := sampling
+		s.ROI = part
+		ps, err := NewPixelSound(traverseFunc, traverseParams, sonifyFunc, sonifyParams, audioFilename, s, sr)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, api.Cursor{PS: ps, Start: s.Snap(bounds, part.Min)})
+	}
+	return cs, nil
+}
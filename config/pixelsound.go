@@ -0,0 +1,76 @@
+// Package config instantiates PixelSounds from the names and parameter values of registered
+// traversals and sonifications, as configured by command line flags shared by the UIs.
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/faiface/beep"
+	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/sonification"
+	"github.com/rytrose/pixelsound/traversal"
+)
+
+// ParamValues is a flag.Value collecting parameter values from repeated name=value flags.
+type ParamValues map[string]string
+
+// String returns the parameter values as comma separated name=value pairs.
+func (p ParamValues) String() string {
+	pairs := make([]string, 0, len(p))
+	for name, value := range p {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set adds a name=value parameter value.
+func (p ParamValues) Set(s string) error {
+	split := strings.SplitN(s, "=", 2)
+	if len(split) != 2 {
+		return fmt.Errorf("parameter %q must be formatted name=value", s)
+	}
+	p[split[0]] = split[1]
+	return nil
+}
+
+// NewPixelSound instantiates a PixelSound from the names and parameter values of a registered
+// traversal and a registered sonification. audioFilename is used as the "audio"
+// parameter of sonifications that have one, if it isn't otherwise set. The traversal
+// traverses the image as configured by sampling.
+func NewPixelSound(traverseFunc string, traverseParams ParamValues, sonifyFunc string, sonifyParams ParamValues, audioFilename string, sampling traversal.Sampling, sr beep.SampleRate) (api.PixelSound, error) {
+	t, err := traversal.New(traverseFunc, traverseParams)
+	if err != nil {
+		return nil, err
+	}
+	t = sampling.Wrap(t)
+
+	if s, ok := sonification.Lookup(sonifyFunc); ok && audioFilename != "" {
+		if _, ok := s.Params.Lookup("audio"); ok {
+			if _, ok := sonifyParams["audio"]; !ok {
+				sonifyParams = copyParams(sonifyParams)
+				sonifyParams["audio"] = audioFilename
+			}
+		}
+	}
+	s, err := sonification.New(sonifyFunc, sr, sonifyParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.PixelSounder{
+		Tr: t,
+		S:  s,
+	}, nil
+}
+
+// copyParams returns a copy of parameter values.
+func copyParams(p ParamValues) ParamValues {
+	c := ParamValues{}
+	for name, value := range p {
+		c[name] = value
+	}
+	return c
+}
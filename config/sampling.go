@@ -0,0 +1,53 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"image"
+
+	"github.com/rytrose/pixelsound/traversal"
+)
+
+// SamplingFlags defines the -stride, -block and -roi flags on a FlagSet, returning the Sampling
+// they configure once the flags are parsed.
+func SamplingFlags(flags *flag.FlagSet) *traversal.Sampling {
+	s := &traversal.Sampling{}
+	flags.IntVar(&s.Stride, "stride", 1, "traverse every Nth pixel (or block) in each direction")
+	flags.IntVar(&s.Block, "block", 1, "traverse N by N blocks of pixels as single pixels with their average color")
+	flags.Var((*rectValue)(&s.ROI), "roi", "only traverse the pixels within the rectangle x0,y0,x1,y1")
+	return s
+}
+
+// CheckSampling returns an error if the region of interest of a Sampling is outside of an image
+// with the provided bounds, so that no pixel would be traversed.
+func CheckSampling(s traversal.Sampling, bounds image.Rectangle) error {
+	if !s.ROI.Empty() && !s.ROI.Overlaps(bounds) {
+		return fmt.Errorf("region of interest %s is outside of the image bounds %s", s.ROI, bounds)
+	}
+	return nil
+}
+
+// rectValue is a flag.Value for a rectangle formatted as x0,y0,x1,y1.
+type rectValue image.Rectangle
+
+// String returns the rectangle formatted as x0,y0,x1,y1.
+func (r *rectValue) String() string {
+	if r == nil || image.Rectangle(*r).Empty() {
+		return ""
+	}
+	return fmt.Sprintf("%d,%d,%d,%d", r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
+}
+
+// Set parses a non-empty rectangle formatted as x0,y0,x1,y1.
+func (r *rectValue) Set(s string) error {
+	var x0, y0, x1, y1 int
+	if _, err := fmt.Sscanf(s, "%d,%d,%d,%d", &x0, &y0, &x1, &y1); err != nil {
+		return fmt.Errorf("rectangle %q must be formatted x0,y0,x1,y1", s)
+	}
+	rect := image.Rect(x0, y0, x1, y1)
+	if rect.Empty() {
+		return fmt.Errorf("rectangle %q is empty", s)
+	}
+	*r = rectValue(rect)
+	return nil
+}
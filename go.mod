@@ -7,6 +7,7 @@ require (
 	github.com/faiface/pixel v0.10.0
 	github.com/go-humble/detect v0.1.2
 	github.com/google/uuid v1.3.0
+	github.com/mewkiz/flac v1.0.7
 	github.com/nfnt/resize v0.0.0-20180221191011-83c6a9932646
 	github.com/oskca/gopherjs-canvas v0.0.0-20180117053031-a5c176020cfd
 	github.com/oskca/gopherjs-dom v0.0.0-20170212055251-32ec7beb35cf
@@ -28,7 +29,6 @@ require (
 	github.com/icza/bitio v1.1.0 // indirect
 	github.com/jfreymuth/oggvorbis v1.0.3 // indirect
 	github.com/jfreymuth/vorbis v1.0.2 // indirect
-	github.com/mewkiz/flac v1.0.7 // indirect
 	github.com/mewkiz/pkg v0.0.0-20211102230744-16a6ce8f1b77 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/stretchr/testify v1.7.0 // indirect
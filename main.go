@@ -1,7 +1,13 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"os"
+
 	"github.com/go-humble/detect"
+	"github.com/rytrose/pixelsound/cli"
+	"github.com/rytrose/pixelsound/log"
 	"github.com/rytrose/pixelsound/ui"
 	"github.com/rytrose/pixelsound/ui/browser"
 	"github.com/rytrose/pixelsound/ui/thick"
@@ -13,17 +19,52 @@ func main() {
 	if detect.IsBrowser() {
 		ui = setupJS()
 	} else {
-		ui = setupDarwin()
+		args := os.Args[1:]
+		if len(args) > 0 {
+			if args[0] == "help" {
+				cli.Usage(os.Stdout)
+				return
+			}
+			// Run a subcommand, which may not need a UI at all
+			if cmd, ok := cli.Lookup(args[0]); ok {
+				if err := cmd.Run(args[1:]); err != nil && !errors.Is(err, flag.ErrHelp) {
+					log.Fatal(err)
+				}
+				return
+			}
+		}
+		// Without a subcommand, flags are for the thick client
+		ui = setupDarwin(args)
+		if ui == nil {
+			// Built with the headless tag, without a window
+			cli.Usage(os.Stderr)
+			os.Exit(2)
+		}
 	}
 
 	// Run the UI
 	ui.Run()
 }
 
+func init() {
+	cli.Register(&cli.Command{
+		Name:    "play",
+		Summary: "play a traversal of an image in a window, or play pixels with the mouse or keyboard",
+		Run: func(args []string) error {
+			ui := setupDarwin(args)
+			if ui == nil {
+				return errors.New("play needs a window, which isn't available in a headless build")
+			}
+			ui.Run()
+			return nil
+		},
+	})
+}
+
 func setupJS() ui.UI {
 	return browser.NewBrowser()
 }
 
-func setupDarwin() ui.UI {
-	return thick.NewThickClient()
+func setupDarwin(args []string) ui.UI {
+	return thick.NewThickClient(args)
 }
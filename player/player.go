@@ -2,11 +2,13 @@ package player
 
 import (
 	"image"
+	"sync"
+	"time"
 
 	"github.com/faiface/beep"
 	"github.com/faiface/beep/effects"
-	"github.com/faiface/beep/speaker"
 	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/log"
 	"github.com/rytrose/pixelsound/util"
 )
 
@@ -14,22 +16,38 @@ import (
 type Player struct {
 	sr             beep.SampleRate   // Sample rate of playback
 	bs             int               // Buffer size of playback
+	sink           Sink              // Audio output pulling samples from the Streamers
+	mu             sync.Mutex        // Lock for the image, PixelSound, queues and cursors being played
 	i              image.Image       // Image being played
-	ps             api.PixelSound    // Algorithms for traversal and sonification
-	loc            image.Point       // Pixel location
-	state          interface{}       // Previous state from sonification
-	q              *Queue            // Streamer to queue up playback
+	ps             api.PixelSound    // Algorithms for traversal and sonification of single pixels
+	queues         []*Queue          // Streamers to queue up playback of each cursor, the first also plays single pixels
+	cursors        []*cursor         // Traversals being played
+	m              *beep.Mixer       // Streamer to mix the queues together
+	declick        int               // Number of samples to smooth the joins between pixels over
 	c              *beep.Ctrl        // Streamer to play/pause
 	v              *effects.Volume   // Streamer to control volume
-	PointChan      chan image.Point  // Writes the point being played
+	PointChan      chan image.Point  // Writes the point being played by any cursor
 	usePointChan   bool              // If set, writes the point being played to PointChan
 	LatestPoint    *image.Point      // The latest played point, access requires PointLock
-	PointLock      util.PriorityLock // Lock for reading/writing the latest played point
-	useLatestPoint bool              // If set, writes the point being played to LatestPoint
+	LatestPoints   []image.Point     // The latest played point of each cursor, access requires PointLock
+	PointLock      util.PriorityLock // Lock for reading/writing the latest played points
+	useLatestPoint bool              // If set, writes the point being played to LatestPoint and LatestPoints
+}
+
+// cursor is a traversal being played by a Player.
+type cursor struct {
+	index   int            // Index of the cursor in queues and LatestPoints
+	q       *Queue         // Streamer to queue up playback of the traversal
+	i       image.Image    // Image being traversed
+	ps      api.PixelSound // Algorithms for traversal and sonification
+	mu      sync.Mutex     // Lock for traversing
+	stopped bool           // If set, the traversal was replaced and no longer continues
 }
 
 type PlayerOpt func(*Player)
 
+// WithPointChan writes the point being played by any cursor to PointChan. Points are dropped
+// while PointChan is full, so that a slow reader never blocks playback.
 func WithPointChan() PlayerOpt {
 	return func(p *Player) {
 		p.usePointChan = true
@@ -42,15 +60,32 @@ func WithLatestPoint() PlayerOpt {
 	}
 }
 
+// WithSink sets the audio output of the player, which defaults to the speaker.
+func WithSink(s Sink) PlayerOpt {
+	return func(p *Player) {
+		p.sink = s
+	}
+}
+
+// WithDeclick smooths the joins between pixel Streamers over a duration, removing the clicks
+// caused by jumps between the end of one pixel and the start of the next.
+func WithDeclick(d time.Duration) PlayerOpt {
+	return func(p *Player) {
+		p.declick = p.sr.N(d)
+		for _, q := range p.queues {
+			q.SetDeclick(p.declick)
+		}
+	}
+}
+
 // NewPlayer creates a Player.
 func NewPlayer(sampleRate beep.SampleRate, bufferSize int, opts ...PlayerOpt) *Player {
-	// Initialize the speaker
-	speaker.Init(sampleRate, bufferSize)
-
 	// Setup beep streamers
 	q := &Queue{}
+	m := &beep.Mixer{}
+	m.Add(q)
 	c := &beep.Ctrl{
-		Streamer: q,
+		Streamer: m,
 		Paused:   false,
 	}
 	v := &effects.Volume{
@@ -60,16 +95,15 @@ func NewPlayer(sampleRate beep.SampleRate, bufferSize int, opts ...PlayerOpt) *P
 		Silent:   false,
 	}
 
-	// Start playing (plays silence until something is added)
-	speaker.Play(q)
-
 	// Define Player
 	p := &Player{
-		sr: sampleRate,
-		bs: bufferSize,
-		q:  q,
-		c:  c,
-		v:  v,
+		sr:     sampleRate,
+		bs:     bufferSize,
+		sink:   NewSpeakerSink(),
+		queues: []*Queue{q},
+		m:      m,
+		c:      c,
+		v:      v,
 		// Buffer so that very fast calls to PlayPixel don't get behind if the
 		// reader is slow
 		PointChan: make(chan image.Point, 60),
@@ -81,147 +115,229 @@ func NewPlayer(sampleRate beep.SampleRate, bufferSize int, opts ...PlayerOpt) *P
 		o(p)
 	}
 
+	// Initialize the audio output
+	if err := p.sink.Init(sampleRate, bufferSize); err != nil {
+		log.Printf("unable to initialize audio output: %s", err)
+	}
+
+	// Start playing (plays silence until something is added)
+	p.sink.Play(v)
+
 	return p
 }
 
 // SetImagePixelSound sets the current image and PixelSound.
 func (p *Player) SetImagePixelSound(image image.Image, ps api.PixelSound) {
+	p.mu.Lock()
 	p.i = image
 	p.ps = ps
+	p.mu.Unlock()
 }
 
 // SetImagePixelSound sets the current image.
 func (p *Player) SetImage(image image.Image) {
+	p.mu.Lock()
 	p.i = image
+	p.mu.Unlock()
 }
 
 // SetPixelSound sets the current PixelSound.
 func (p *Player) SetPixelSound(ps api.PixelSound) {
+	p.mu.Lock()
 	p.ps = ps
+	p.mu.Unlock()
 }
 
 // Play plays a provided PixelSound for an image starting from provided coordinates.
-func (p *Player) Play(image image.Image, ps api.PixelSound, start image.Point, state interface{}) {
-	// Save playing image, PixelSound, and starting coordinates
-	p.i = image
-	p.ps = ps
-	p.loc = start
+func (p *Player) Play(image image.Image, ps api.PixelSound, start image.Point) {
+	p.PlayCursors(image, []api.Cursor{{PS: ps, Start: start}})
+}
+
+// PlayCursors plays the traversals of multiple cursors over an image at once, mixed together
+// at equal volume. Each traversal starts from the first pixel its Traverser returns. The first
+// cursor's PixelSound is also used to play single pixels.
+func (p *Player) PlayCursors(im image.Image, cursors []api.Cursor) {
+	if len(cursors) == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Save playing image and PixelSound
+	p.i = im
+	p.ps = cursors[0].PS
 
 	// Stop anything playing previously
-	p.q.Clear()
+	p.stop()
+
+	// Setup a queue for each cursor
+	if len(cursors) != len(p.queues) {
+		p.setQueues(len(cursors))
+	}
+	if p.useLatestPoint {
+		p.PointLock.Lock()
+		p.LatestPoints = make([]image.Point, len(cursors))
+		p.PointLock.Unlock()
+	}
+
+	p.cursors = make([]*cursor, len(cursors))
+	for i, c := range cursors {
+		pc := &cursor{
+			index: i,
+			q:     p.queues[i],
+			i:     im,
+			ps:    c.PS,
+		}
+		p.cursors[i] = pc
+		c.PS.Reset(im.Bounds(), im, c.Start)
 
-	// Get the first pixel Streamer
-	s, state := ps.Sonify(p.i.At(p.loc.X, p.loc.Y), p.sr, state)
-	p.state = state
+		// Start playback by queueing up the first pixel Streamer
+		p.next(pc)
+	}
+}
 
-	// Call the next pixel Streamer after the first is done
-	n := beep.Seq(beep.Callback(func() {
-		// Update with the first point
-		p.updatePoint()
-	}), s, beep.Callback(p.next))
+// setQueues replaces the queues with n queues, each mixed in at 1/n volume. p.mu must be held.
+func (p *Player) setQueues(n int) {
+	queues := make([]*Queue, n)
+	streamers := make([]beep.Streamer, n)
+	for i := range queues {
+		q := &Queue{}
+		q.SetDeclick(p.declick)
+		queues[i] = q
+		streamers[i] = q
+		if n > 1 {
+			streamers[i] = &effects.Gain{Streamer: q, Gain: 1/float64(n) - 1}
+		}
+	}
 
-	// Start playback by adding to mixer
-	p.q.Add(n)
+	p.sink.Lock()
+	p.m.Clear()
+	p.m.Add(streamers...)
+	p.sink.Unlock()
+	p.queues = queues
 }
 
-// next traverses the PixelSound and queues up the next pixel Streamer, if there is one.
-func (p *Player) next() {
-	var ok bool
-	p.loc, ok = p.ps.Traverse(p.loc, p.i.Bounds())
-	p.updatePoint()
-	if ok {
-		// Add this pixel Streamer, then the next
-		s, state := p.ps.Sonify(p.i.At(p.loc.X, p.loc.Y), p.sr, p.state)
-		p.state = state
-		p.q.Add(beep.Seq(s, beep.Callback(p.next)))
-	} else {
-		// Add the final pixel Streamer
-		s, state := p.ps.Sonify(p.i.At(p.loc.X, p.loc.Y), p.sr, p.state)
-		p.state = state
-		p.q.Add(s)
+// next traverses a cursor's PixelSound and queues up the next pixel Streamer, if there is one.
+func (p *Player) next(c *cursor) {
+	c.mu.Lock()
+	if c.stopped {
+		c.mu.Unlock()
+		return
 	}
+	loc, ok := c.ps.Next()
+	if !ok {
+		c.mu.Unlock()
+		return
+	}
+
+	// Add this pixel Streamer, then the next
+	s := api.SonifyAt(c.ps, c.i, loc, p.sr)
+	c.q.Add(beep.Seq(s, beep.Callback(func() {
+		p.next(c)
+	})))
+	c.mu.Unlock()
+
+	p.updatePoint(c.index, loc)
 }
 
 // PlayPixel plays the pixel at the provided point.
-func (p *Player) PlayPixel(point image.Point, queue bool, state interface{}) {
-	p.loc = point
-	p.updatePoint()
-	sonifyState := p.state
-	if state != nil {
-		sonifyState = state
-	}
-	s, state := p.ps.Sonify(p.i.At(point.X, point.Y), p.sr, sonifyState)
-	p.state = state
+func (p *Player) PlayPixel(point image.Point, queue bool) {
+	p.updatePoint(0, point)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := api.SonifyAt(p.ps, p.i, point, p.sr)
 	if !queue {
-		p.q.Clear()
+		p.stop()
 	}
-	p.q.Add(s)
+	p.queues[0].Add(s)
 }
 
-// updatePoint sends the currently playing point through PointChan,
-// and/or updates LatestPoint.
-func (p *Player) updatePoint() {
+// updatePoint sends the point a cursor is playing through PointChan,
+// and/or updates LatestPoint and LatestPoints. Points are dropped while PointChan is full,
+// so that a slow reader never blocks playback.
+func (p *Player) updatePoint(index int, point image.Point) {
 	if p.usePointChan {
-		p.PointChan <- p.loc
+		select {
+		case p.PointChan <- point:
+		default:
+		}
 	}
 	if p.useLatestPoint {
 		p.PointLock.Lock()
-		p.LatestPoint = &p.loc
+		p.LatestPoint = &point
+		if index < len(p.LatestPoints) {
+			p.LatestPoints[index] = point
+		}
 		p.PointLock.Unlock()
 	}
 }
 
-// Stop clears the queue to stop playback.
+// Stop stops the traversals being played and clears the queues to stop playback.
 func (p *Player) Stop() {
-	p.q.Clear()
+	p.mu.Lock()
+	p.stop()
+	p.mu.Unlock()
+}
+
+// stop stops the traversals being played and clears the queues. p.mu must be held.
+func (p *Player) stop() {
+	for _, c := range p.cursors {
+		c.mu.Lock()
+		c.stopped = true
+		c.mu.Unlock()
+	}
+	p.cursors = nil
+	for _, q := range p.queues {
+		q.Clear()
+	}
 }
 
 // TogglePlayback toggles the playing/paused state of the player.
 func (p *Player) TogglePlayback() {
-	speaker.Lock()
+	p.sink.Lock()
 	p.c.Paused = !p.c.Paused
-	speaker.Unlock()
+	p.sink.Unlock()
 }
 
 // Pause pauses the playback state of the player.
 func (p *Player) Pause() {
-	speaker.Lock()
+	p.sink.Lock()
 	p.c.Paused = true
-	speaker.Unlock()
+	p.sink.Unlock()
 }
 
 // Resume resumes the playback state of the player.
 func (p *Player) Resume() {
-	speaker.Lock()
+	p.sink.Lock()
 	p.c.Paused = false
-	speaker.Unlock()
+	p.sink.Unlock()
 }
 
 // Mute mutes the playback of the player.
 func (p *Player) Mute() {
-	speaker.Lock()
+	p.sink.Lock()
 	p.v.Silent = true
-	speaker.Unlock()
+	p.sink.Unlock()
 }
 
 // Unmute unmutes the playback of the player.
 func (p *Player) Unmute() {
-	speaker.Lock()
+	p.sink.Lock()
 	p.v.Silent = false
-	speaker.Unlock()
+	p.sink.Unlock()
 }
 
 // ToggleMute toggles the mute status of the playback of the player.
 func (p *Player) ToggleMute() {
-	speaker.Lock()
+	p.sink.Lock()
 	p.v.Silent = !p.v.Silent
-	speaker.Unlock()
+	p.sink.Unlock()
 }
 
 // SetVolume sets the volume of the player.
 // 0 is no volume change, negative numbers are quieter, positive numbers are louder.
 func (p *Player) SetVolume(v float64) {
-	speaker.Lock()
+	p.sink.Lock()
 	p.v.Volume = v
-	speaker.Unlock()
+	p.sink.Unlock()
 }
@@ -1,22 +1,52 @@
 package player
 
 import (
+	"sync"
+
 	"github.com/faiface/beep"
 )
 
 // Queue plays streamers sequentially, and otherwise outputs silence.
+// Streamers may be added and cleared concurrently with streaming.
 type Queue struct {
+	mu        sync.Mutex
 	streamers []beep.Streamer
+	clears    uint64 // Number of times the queue has been cleared
+	fade      int    // Number of samples to declick joins over, 0 disables declicking
+
+	// Declicking state, only accessed while streaming
+	seenClears uint64     // Number of clears seen while streaming
+	silent     bool       // If set, silence was streamed last
+	join       bool       // If set, the next sample streamed starts a new Streamer or silence
+	last       [2]float64 // Last sample streamed
+	offset     [2]float64 // Discontinuity at the last join
+	offsetLen  int        // Number of samples the discontinuity is smoothed over
+	offsetLeft int        // Number of samples left to smooth the discontinuity over
 }
 
 // Add adds a Streamer to the queue.
 func (q *Queue) Add(streamers ...beep.Streamer) {
+	q.mu.Lock()
 	q.streamers = append(q.streamers, streamers...)
+	q.mu.Unlock()
 }
 
 // Clear removes all Streamers from the queue.
 func (q *Queue) Clear() {
-	q.streamers = q.streamers[:0]
+	q.mu.Lock()
+	// Don't reuse the backing array, as Stream may still be streaming from it
+	q.streamers = nil
+	q.clears++
+	q.mu.Unlock()
+}
+
+// SetDeclick sets the number of samples over which the jump between the end of one Streamer
+// and the start of the next is smoothed, including when Clear cuts a Streamer off and when the
+// queue runs dry. 0 disables declicking.
+func (q *Queue) SetDeclick(n int) {
+	q.mu.Lock()
+	q.fade = n
+	q.mu.Unlock()
 }
 
 // Stream streams the Streamer at the head of the queue, otherwise it streams silence.
@@ -25,21 +55,49 @@ func (q *Queue) Stream(samples [][2]float64) (n int, ok bool) {
 	// successfully filled already. We loop until all samples are filled.
 	filled := 0
 	for filled < len(samples) {
+		q.mu.Lock()
+		fade := q.fade
+		if q.clears != q.seenClears {
+			q.seenClears = q.clears
+			q.join = true
+		}
 		// There are no streamers in the queue, so we stream silence.
 		if len(q.streamers) == 0 {
-			for i := range samples[filled:] {
-				samples[i][0] = 0
-				samples[i][1] = 0
+			q.mu.Unlock()
+			if !q.silent {
+				q.silent = true
+				q.join = true
+			}
+			silence := samples[filled:]
+			for i := range silence {
+				silence[i][0] = 0
+				silence[i][1] = 0
 			}
+			q.declick(silence, fade)
 			break
 		}
+		head := q.streamers[0]
+		clears := q.clears
+		q.mu.Unlock()
+		if q.silent {
+			q.silent = false
+			q.join = true
+		}
 
-		// We stream from the first streamer in the queue.
-		n, ok := q.streamers[0].Stream(samples[filled:])
+		// We stream from the first streamer in the queue. The lock isn't
+		// held, as streamers may add to the queue from callbacks.
+		n, ok := head.Stream(samples[filled:])
+		q.declick(samples[filled:filled+n], fade)
 		// If it's drained, we pop it from the queue, thus continuing with
-		// the next streamer.
+		// the next streamer. If the queue was cleared while streaming, the
+		// head is already gone.
 		if !ok {
-			q.streamers = q.streamers[1:]
+			q.mu.Lock()
+			if q.clears == clears {
+				q.streamers = q.streamers[1:]
+			}
+			q.mu.Unlock()
+			q.join = true
 		}
 		// We update the number of filled samples.
 		filled += n
@@ -47,6 +105,29 @@ func (q *Queue) Stream(samples [][2]float64) (n int, ok bool) {
 	return len(samples), true
 }
 
+// declick smooths away the jump between the last sample streamed and the first sample
+// following a join, linearly over fade samples.
+func (q *Queue) declick(samples [][2]float64, fade int) {
+	for i := range samples {
+		if q.join {
+			q.join = false
+			if fade > 0 {
+				q.offset[0] = q.last[0] - samples[i][0]
+				q.offset[1] = q.last[1] - samples[i][1]
+				q.offsetLen = fade
+				q.offsetLeft = fade
+			}
+		}
+		if q.offsetLeft > 0 {
+			g := float64(q.offsetLeft) / float64(q.offsetLen)
+			samples[i][0] += q.offset[0] * g
+			samples[i][1] += q.offset[1] * g
+			q.offsetLeft--
+		}
+		q.last = samples[i]
+	}
+}
+
 // Err returns no error.
 func (q *Queue) Err() error {
 	return nil
@@ -0,0 +1,128 @@
+package player
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/faiface/beep"
+)
+
+// level returns a Streamer of n samples at a constant level.
+func level(n int, l float64) beep.Streamer {
+	return beep.Take(n, beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		for i := range samples {
+			samples[i] = [2]float64{l, l}
+		}
+		return len(samples), true
+	}))
+}
+
+// filled returns a buffer of n samples filled with garbage, to check what Stream overwrites.
+func filled(n int) [][2]float64 {
+	samples := make([][2]float64, n)
+	for i := range samples {
+		samples[i] = [2]float64{9, 9}
+	}
+	return samples
+}
+
+func TestQueueSilenceFill(t *testing.T) {
+	tests := []struct {
+		name      string
+		streamers []beep.Streamer
+		want      []float64
+	}{
+		{"empty", nil, []float64{0, 0, 0, 0, 0, 0}},
+		{"short", []beep.Streamer{level(2, 1)}, []float64{1, 1, 0, 0, 0, 0}},
+		{"two short", []beep.Streamer{level(2, 1), level(1, 0.5)}, []float64{1, 1, 0.5, 0, 0, 0}},
+		{"exact", []beep.Streamer{level(6, 1)}, []float64{1, 1, 1, 1, 1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Queue{}
+			q.Add(tt.streamers...)
+			samples := filled(len(tt.want))
+			n, ok := q.Stream(samples)
+			if n != len(samples) || !ok {
+				t.Fatalf("Stream returned %d, %t, want %d, true", n, ok, len(samples))
+			}
+			for i, s := range samples {
+				if s[0] != tt.want[i] || s[1] != tt.want[i] {
+					t.Errorf("sample %d is %v, want %v", i, s, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestQueueAcrossStreams(t *testing.T) {
+	q := &Queue{}
+	q.Add(level(3, 1), level(3, 0.5))
+	var got []float64
+	for i := 0; i < 4; i++ {
+		samples := filled(2)
+		q.Stream(samples)
+		got = append(got, samples[0][0], samples[1][0])
+	}
+	want := []float64{1, 1, 1, 0.5, 0.5, 0.5, 0, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("streamed %v, want %v", got, want)
+		}
+	}
+}
+
+func TestQueueClear(t *testing.T) {
+	q := &Queue{}
+	q.Add(level(4, 1))
+	samples := filled(2)
+	q.Stream(samples)
+	q.Clear()
+	samples = filled(2)
+	q.Stream(samples)
+	if samples[0][0] != 0 || samples[1][0] != 0 {
+		t.Errorf("streamed %v after Clear, want silence", samples)
+	}
+}
+
+// TestQueueConcurrent hammers a Queue with producers adding and clearing while it streams,
+// and streamers adding to it from callbacks. Run with -race.
+func TestQueueConcurrent(t *testing.T) {
+	q := &Queue{}
+	q.SetDeclick(8)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; ; j++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				switch {
+				case i == 0 && j%10 == 0:
+					q.Clear()
+				case i == 1:
+					q.SetDeclick(j % 16)
+				default:
+					// Streamers that queue up another when they end, like the Player's traversals
+					q.Add(beep.Seq(level(j%7, 1), beep.Callback(func() {
+						q.Add(level(3, 0.5))
+					})))
+				}
+			}
+		}(i)
+	}
+
+	samples := make([][2]float64, 64)
+	for i := 0; i < 2000; i++ {
+		if n, ok := q.Stream(samples); n != len(samples) || !ok {
+			t.Fatalf("Stream returned %d, %t, want %d, true", n, ok, len(samples))
+		}
+	}
+	close(done)
+	wg.Wait()
+}
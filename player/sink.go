@@ -0,0 +1,238 @@
+package player
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/speaker"
+	"github.com/rytrose/pixelsound/render"
+)
+
+// Sink is an audio output that pulls samples from a Streamer.
+type Sink interface {
+	// Init prepares the Sink to pull samples at a sample rate, bufferSize samples at a time.
+	Init(sr beep.SampleRate, bufferSize int) error
+	// Play starts pulling samples from a Streamer.
+	Play(s beep.Streamer)
+	// Lock stops the Sink from pulling samples until Unlock is called, so the Streamer can be
+	// modified safely.
+	Lock()
+	// Unlock allows the Sink to pull samples again.
+	Unlock()
+}
+
+// speakerSink plays audio through the speaker.
+type speakerSink struct{}
+
+// NewSpeakerSink returns a Sink that plays audio through the speaker. It is the default Sink.
+func NewSpeakerSink() Sink {
+	return speakerSink{}
+}
+
+// Init initializes the speaker.
+func (speakerSink) Init(sr beep.SampleRate, bufferSize int) error {
+	return speaker.Init(sr, bufferSize)
+}
+
+// Play plays a Streamer through the speaker.
+func (speakerSink) Play(s beep.Streamer) {
+	speaker.Play(s)
+}
+
+// Lock locks the speaker.
+func (speakerSink) Lock() {
+	speaker.Lock()
+}
+
+// Unlock unlocks the speaker.
+func (speakerSink) Unlock() {
+	speaker.Unlock()
+}
+
+// BufferSink stores audio in memory. Samples are only pulled when Pull is called,
+// which makes playback deterministic.
+type BufferSink struct {
+	mu      sync.Mutex
+	sr      beep.SampleRate
+	s       beep.Streamer
+	samples [][2]float64
+}
+
+// NewBufferSink returns a BufferSink.
+func NewBufferSink() *BufferSink {
+	return &BufferSink{}
+}
+
+// Init sets the sample rate of the BufferSink.
+func (b *BufferSink) Init(sr beep.SampleRate, bufferSize int) error {
+	b.sr = sr
+	return nil
+}
+
+// Play sets the Streamer to pull samples from.
+func (b *BufferSink) Play(s beep.Streamer) {
+	b.mu.Lock()
+	b.s = s
+	b.mu.Unlock()
+}
+
+// Lock locks the BufferSink.
+func (b *BufferSink) Lock() {
+	b.mu.Lock()
+}
+
+// Unlock unlocks the BufferSink.
+func (b *BufferSink) Unlock() {
+	b.mu.Unlock()
+}
+
+// Pull pulls n samples from the Streamer into the buffer. Missing samples are filled with
+// silence if the Streamer is drained or hasn't been set.
+func (b *BufferSink) Pull(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	samples := make([][2]float64, n)
+	filled := 0
+	for b.s != nil && filled < n {
+		sn, ok := b.s.Stream(samples[filled:])
+		filled += sn
+		if !ok {
+			b.s = nil
+		}
+	}
+	b.samples = append(b.samples, samples...)
+}
+
+// PullDuration pulls a duration of samples from the Streamer into the buffer.
+func (b *BufferSink) PullDuration(d time.Duration) {
+	b.Pull(b.sr.N(d))
+}
+
+// Samples returns a copy of all samples pulled so far.
+func (b *BufferSink) Samples() [][2]float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([][2]float64(nil), b.samples...)
+}
+
+// Reset discards all samples pulled so far.
+func (b *BufferSink) Reset() {
+	b.mu.Lock()
+	b.samples = b.samples[:0]
+	b.mu.Unlock()
+}
+
+// FileSink writes audio to a file. Like BufferSink, samples are only pulled when Pull is
+// called, and they are encoded when the FileSink is closed.
+type FileSink struct {
+	*BufferSink
+	w io.WriteSeeker
+	f render.Format
+}
+
+// NewFileSink returns a FileSink that writes audio to w in the provided Format.
+func NewFileSink(w io.WriteSeeker, f render.Format) *FileSink {
+	return &FileSink{
+		BufferSink: NewBufferSink(),
+		w:          w,
+		f:          f,
+	}
+}
+
+// Close encodes all samples pulled so far.
+func (f *FileSink) Close() error {
+	samples := f.Samples()
+	format := beep.Format{
+		SampleRate:  f.sr,
+		NumChannels: 2,
+		Precision:   2,
+	}
+	return render.Encode(f.w, &sliceStreamer{samples: samples}, format, f.f)
+}
+
+// NullSink discards audio, pulling samples in real time as if it were a speaker.
+type NullSink struct {
+	mu       sync.Mutex
+	s        beep.Streamer
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewNullSink returns a NullSink.
+func NewNullSink() *NullSink {
+	return &NullSink{
+		stop: make(chan struct{}),
+	}
+}
+
+// Init starts pulling bufferSize samples at a time in real time.
+func (n *NullSink) Init(sr beep.SampleRate, bufferSize int) error {
+	go func() {
+		samples := make([][2]float64, bufferSize)
+		ticker := time.NewTicker(sr.D(bufferSize))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				n.mu.Lock()
+				if n.s != nil {
+					if _, ok := n.s.Stream(samples); !ok {
+						n.s = nil
+					}
+				}
+				n.mu.Unlock()
+			case <-n.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Play sets the Streamer to pull samples from.
+func (n *NullSink) Play(s beep.Streamer) {
+	n.mu.Lock()
+	n.s = s
+	n.mu.Unlock()
+}
+
+// Lock locks the NullSink.
+func (n *NullSink) Lock() {
+	n.mu.Lock()
+}
+
+// Unlock unlocks the NullSink.
+func (n *NullSink) Unlock() {
+	n.mu.Unlock()
+}
+
+// Close stops pulling samples. Closing more than once has no effect.
+func (n *NullSink) Close() error {
+	n.stopOnce.Do(func() {
+		close(n.stop)
+	})
+	return nil
+}
+
+// sliceStreamer streams samples from a slice.
+type sliceStreamer struct {
+	samples [][2]float64
+}
+
+// Stream streams the next samples of the slice.
+func (s *sliceStreamer) Stream(samples [][2]float64) (n int, ok bool) {
+	if len(s.samples) == 0 {
+		return 0, false
+	}
+	n = copy(samples, s.samples)
+	s.samples = s.samples[n:]
+	return n, true
+}
+
+// Err returns no error.
+func (s *sliceStreamer) Err() error {
+	return nil
+}
@@ -0,0 +1,136 @@
+package player
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/traversal"
+	"github.com/rytrose/pixelsound/util"
+)
+
+// pixelSamples is the number of samples each pixel plays for in tests.
+const pixelSamples = 4
+
+// redLevel is a SonifyFunc playing the red channel of a color as a constant level.
+var redLevel = api.SonifyFunc(func(c color.Color, sr beep.SampleRate) beep.Streamer {
+	r, _, _, _ := util.FloatRGBA(c)
+	return beep.Take(pixelSamples, beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		for i := range samples {
+			samples[i] = [2]float64{r, r}
+		}
+		return len(samples), true
+	}))
+})
+
+// levelImage returns a one row image whose pixels have the provided levels of red.
+func levelImage(levels ...uint8) image.Image {
+	im := image.NewRGBA(image.Rect(0, 0, len(levels), 1))
+	for x, l := range levels {
+		im.Set(x, 0, color.RGBA{l, 0, 0, 255})
+	}
+	return im
+}
+
+func TestBufferSinkPlay(t *testing.T) {
+	sink := NewBufferSink()
+	p := NewPlayer(44100, 512, WithSink(sink))
+	ps := &api.PixelSounder{T: traversal.TtoBLtoR, S: redLevel}
+	p.Play(levelImage(255, 51, 102), ps, image.Point{})
+
+	// Each pixel plays in traversal order, then the queue plays silence
+	sink.Pull(4 * pixelSamples)
+	want := []float64{1, 0.2, 0.4, 0}
+	samples := sink.Samples()
+	if len(samples) != len(want)*pixelSamples {
+		t.Fatalf("pulled %d samples, want %d", len(samples), len(want)*pixelSamples)
+	}
+	for i, s := range samples {
+		w := want[i/pixelSamples]
+		if s[0] != w || s[1] != w {
+			t.Errorf("sample %d is %v, want %v", i, s, w)
+		}
+	}
+}
+
+func TestBufferSinkReset(t *testing.T) {
+	sink := NewBufferSink()
+	p := NewPlayer(44100, 512, WithSink(sink))
+	ps := &api.PixelSounder{T: traversal.TtoBLtoR, S: redLevel}
+	p.Play(levelImage(255, 255), ps, image.Point{})
+
+	sink.Pull(pixelSamples)
+	sink.Reset()
+	sink.Pull(2 * pixelSamples)
+	samples := sink.Samples()
+	if len(samples) != 2*pixelSamples {
+		t.Fatalf("pulled %d samples after reset, want %d", len(samples), 2*pixelSamples)
+	}
+	if samples[0][0] != 1 || samples[pixelSamples][0] != 0 {
+		t.Errorf("samples after reset are %v, want the second pixel then silence", samples)
+	}
+}
+
+func TestNullSinkCloseTwice(t *testing.T) {
+	n := NewNullSink()
+	if err := n.Init(44100, 512); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPointChanUnread(t *testing.T) {
+	sink := NewBufferSink()
+	p := NewPlayer(44100, 512, WithSink(sink), WithPointChan())
+	ps := &api.PixelSounder{T: traversal.TtoBLtoR, S: redLevel}
+	p.Play(levelImage(make([]uint8, 2*cap(p.PointChan))...), ps, image.Point{})
+
+	// Playing more points than PointChan holds drops them instead of blocking
+	done := make(chan struct{})
+	go func() {
+		sink.Pull(4 * cap(p.PointChan) * pixelSamples)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("playback blocked on an unread PointChan")
+	}
+	if len(p.PointChan) != cap(p.PointChan) {
+		t.Errorf("PointChan holds %d points, want %d", len(p.PointChan), cap(p.PointChan))
+	}
+}
+
+func TestPlayPixelWhilePlayingCursors(t *testing.T) {
+	sink := NewBufferSink()
+	p := NewPlayer(44100, 512, WithSink(sink))
+	im := levelImage(255, 51, 102)
+	p.SetImagePixelSound(im, &api.PixelSounder{T: traversal.TtoBLtoR, S: redLevel})
+
+	// Playing pixels while other goroutines replace the cursors, queues and image is race free
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			cursors := []api.Cursor{{PS: &api.PixelSounder{T: traversal.TtoBLtoR, S: redLevel}}}
+			if i%2 == 0 {
+				cursors = append(cursors, api.Cursor{PS: &api.PixelSounder{T: traversal.TtoBLtoR, S: redLevel}})
+			}
+			p.PlayCursors(im, cursors)
+			p.SetImage(im)
+			sink.Pull(pixelSamples)
+		}
+		close(done)
+	}()
+	for i := 0; i < 100; i++ {
+		p.PlayPixel(image.Point{X: i % 3}, i%2 == 0)
+	}
+	<-done
+}
@@ -0,0 +1,125 @@
+package render
+
+import (
+	"errors"
+	"io"
+	"math"
+
+	"github.com/faiface/beep"
+	"github.com/mewkiz/flac"
+	"github.com/mewkiz/flac/frame"
+	"github.com/mewkiz/flac/meta"
+)
+
+// flacBlockSize is the number of samples per channel in each FLAC frame.
+const flacBlockSize = 4000
+
+// flacMinBlockSize is the minimum number of samples per channel in a FLAC frame.
+const flacMinBlockSize = 16
+
+// flacBadBlockSizes are the block sizes for which flac.Encoder writes frame headers with the
+// wrong block size, so that the file can't be decoded. Frames of these sizes are split in two.
+var flacBadBlockSizes = map[int]bool{1024: true, 2048: true, 2304: true, 4096: true, 4608: true, 8192: true, 16384: true, 32768: true}
+
+// writeSeeker hides any io.Closer of the underlying writer, as flac.Encoder closes it otherwise.
+type writeSeeker struct {
+	io.WriteSeeker
+}
+
+// encodeFLAC writes all audio streamed from s to w in FLAC format.
+// Frames are stored verbatim, i.e. uncompressed.
+func encodeFLAC(w io.WriteSeeker, s beep.Streamer, format beep.Format) error {
+	if format.NumChannels != 1 && format.NumChannels != 2 {
+		return errors.New("flac: unsupported number of channels, 1 or 2 is supported")
+	}
+	if format.Precision != 1 && format.Precision != 2 && format.Precision != 3 {
+		return errors.New("flac: unsupported precision, 1, 2 or 3 is supported")
+	}
+	bps := uint8(8 * format.Precision)
+	channels := frame.ChannelsLR
+	if format.NumChannels == 1 {
+		channels = frame.ChannelsMono
+	}
+
+	info := &meta.StreamInfo{
+		BlockSizeMin:  flacBlockSize,
+		BlockSizeMax:  flacBlockSize,
+		SampleRate:    uint32(format.SampleRate),
+		NChannels:     uint8(format.NumChannels),
+		BitsPerSample: bps,
+	}
+	enc, err := flac.NewEncoder(writeSeeker{w}, info)
+	if err != nil {
+		return err
+	}
+
+	samples := make([][2]float64, flacBlockSize)
+	for {
+		n, ok := fill(s, samples)
+		if n == 0 {
+			break
+		}
+		// Frames must hold at least flacMinBlockSize samples, so pad the last one with silence
+		size := n
+		if size < flacMinBlockSize {
+			for i := n; i < flacMinBlockSize; i++ {
+				samples[i] = [2]float64{}
+			}
+			size = flacMinBlockSize
+		}
+		frames := [][][2]float64{samples[:size]}
+		if flacBadBlockSizes[size] {
+			split := size - flacMinBlockSize
+			frames = [][][2]float64{samples[:split], samples[split:size]}
+		}
+		for _, fs := range frames {
+			if err := enc.WriteFrame(flacFrame(fs, format, channels, bps)); err != nil {
+				return err
+			}
+		}
+		if !ok {
+			break
+		}
+	}
+	return enc.Close()
+}
+
+// flacFrame returns a frame storing samples verbatim.
+func flacFrame(samples [][2]float64, format beep.Format, channels frame.Channels, bps uint8) *frame.Frame {
+	max := float64(int32(1)<<(bps-1) - 1)
+	subframes := make([]*frame.Subframe, format.NumChannels)
+	for c := range subframes {
+		subframes[c] = &frame.Subframe{
+			SubHeader: frame.SubHeader{Pred: frame.PredVerbatim},
+			Samples:   make([]int32, len(samples)),
+			NSamples:  len(samples),
+		}
+		for i := range samples {
+			v := math.Max(-1, math.Min(1, samples[i][c]))
+			subframes[c].Samples[i] = int32(math.Round(v * max))
+		}
+	}
+	return &frame.Frame{
+		Header: frame.Header{
+			HasFixedBlockSize: false,
+			BlockSize:         uint16(len(samples)),
+			SampleRate:        uint32(format.SampleRate),
+			Channels:          channels,
+			BitsPerSample:     bps,
+		},
+		Subframes: subframes,
+	}
+}
+
+// fill streams from s until samples is full or s is drained.
+// Returns the number of samples filled, and whether s may have more samples.
+func fill(s beep.Streamer, samples [][2]float64) (n int, ok bool) {
+	for n < len(samples) {
+		sn, sok := s.Stream(samples[n:])
+		n += sn
+		if !sok {
+			return n, false
+		}
+	}
+	return n, true
+}
@@ -0,0 +1,57 @@
+package render
+
+import "github.com/faiface/beep"
+
+// mixStreamer streams Streamers mixed together at equal volume until all of them are drained.
+type mixStreamer struct {
+	streamers []beep.Streamer
+	gain      float64
+	buf       [][2]float64
+	err       error
+}
+
+// mix returns a Streamer mixing Streamers together at equal volume, or the Streamer if there is only one.
+func mix(streamers ...beep.Streamer) beep.Streamer {
+	if len(streamers) == 1 {
+		return streamers[0]
+	}
+	return &mixStreamer{
+		streamers: streamers,
+		gain:      1 / float64(len(streamers)),
+	}
+}
+
+// Stream streams the sum of the Streamers that aren't drained yet.
+func (m *mixStreamer) Stream(samples [][2]float64) (n int, ok bool) {
+	if len(m.buf) < len(samples) {
+		m.buf = make([][2]float64, len(samples))
+	}
+	for i := range samples {
+		samples[i] = [2]float64{}
+	}
+	for i := 0; i < len(m.streamers); i++ {
+		s := m.streamers[i]
+		sn, sok := s.Stream(m.buf[:len(samples)])
+		for j := range m.buf[:sn] {
+			samples[j][0] += m.buf[j][0] * m.gain
+			samples[j][1] += m.buf[j][1] * m.gain
+		}
+		if sn > n {
+			n = sn
+		}
+		if !sok || sn < len(samples) {
+			// Remove the drained Streamer
+			if err := s.Err(); err != nil && m.err == nil {
+				m.err = err
+			}
+			m.streamers = append(m.streamers[:i], m.streamers[i+1:]...)
+			i--
+		}
+	}
+	return n, n > 0
+}
+
+// Err returns the first error encountered by a Streamer.
+func (m *mixStreamer) Err() error {
+	return m.err
+}
@@ -0,0 +1,187 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+	"github.com/rytrose/pixelsound/api"
+)
+
+// Format is an audio file format a render can be encoded to.
+type Format int
+
+const (
+	WAV Format = iota
+	FLAC
+)
+
+// FormatFromExt returns the Format corresponding to a file extension, e.g. "wav" or ".flac".
+func FormatFromExt(ext string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(ext, ".")) {
+	case "wav":
+		return WAV, nil
+	case "flac":
+		return FLAC, nil
+	}
+	return WAV, fmt.Errorf("unable to encode audio file with extension %s", ext)
+}
+
+// Encode writes all audio streamed from s to w in the provided Format.
+func Encode(w io.WriteSeeker, s beep.Streamer, format beep.Format, f Format) error {
+	switch f {
+	case WAV:
+		return wav.Encode(w, s, format)
+	case FLAC:
+		return encodeFLAC(w, s, format)
+	}
+	return fmt.Errorf("unknown format %d", f)
+}
+
+// Render traverses and sonifies an image starting from the provided coordinates, writing
+// the resulting audio to w as fast as possible.
+func Render(w io.WriteSeeker, f Format, im image.Image, ps api.PixelSound, start image.Point, sr beep.SampleRate, opts ...RenderOpt) error {
+	return RenderCursors(w, f, im, []api.Cursor{{PS: ps, Start: start}}, sr, opts...)
+}
+
+// RenderCursors traverses and sonifies an image with multiple cursors at once, mixed together
+// at equal volume, writing the resulting audio to w as fast as possible. The options apply to
+// each cursor.
+func RenderCursors(w io.WriteSeeker, f Format, im image.Image, cursors []api.Cursor, sr beep.SampleRate, opts ...RenderOpt) error {
+	if len(cursors) == 0 {
+		return fmt.Errorf("no cursors to render")
+	}
+	streamers := make([]beep.Streamer, len(cursors))
+	for i, c := range cursors {
+		streamers[i] = NewStreamer(im, c.PS, c.Start, sr, opts...)
+	}
+	s := mix(streamers...)
+	format := beep.Format{
+		SampleRate:  sr,
+		NumChannels: 2,
+		Precision:   2,
+	}
+	if err := Encode(w, s, format, f); err != nil {
+		return err
+	}
+	return s.Err()
+}
+
+// RenderFile renders to a file at the provided path, choosing the Format from the file's extension.
+func RenderFile(path string, im image.Image, ps api.PixelSound, start image.Point, sr beep.SampleRate, opts ...RenderOpt) error {
+	f, err := FormatFromExt(filepath.Ext(path))
+	if err != nil {
+		return err
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := Render(out, f, im, ps, start, sr, opts...); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// traversalStreamer streams the sonification of every pixel of a traversal, one after another.
+type traversalStreamer struct {
+	im          image.Image    // Image being rendered
+	ps          api.PixelSound // Algorithms for traversal and sonification
+	sr          beep.SampleRate
+	loc         image.Point   // Pixel location
+	cur         beep.Streamer // Streamer of the current pixel, nil once finished
+	pixels      int           // Number of pixels sonified so far
+	maxPixels   int           // If positive, the maximum number of pixels to sonify
+	maxDuration time.Duration // If positive, the maximum duration to render
+	err         error
+}
+
+type RenderOpt func(*traversalStreamer)
+
+// WithMaxPixels stops the render after n pixels, which is needed for traversals that never finish.
+func WithMaxPixels(n int) RenderOpt {
+	return func(t *traversalStreamer) {
+		t.maxPixels = n
+	}
+}
+
+// WithMaxDuration stops the render after d of audio has been rendered.
+func WithMaxDuration(d time.Duration) RenderOpt {
+	return func(t *traversalStreamer) {
+		t.maxDuration = d
+	}
+}
+
+// NewStreamer returns a beep.Streamer that traverses and sonifies an image starting from the
+// provided coordinates, and drains once the traversal is finished.
+func NewStreamer(im image.Image, ps api.PixelSound, start image.Point, sr beep.SampleRate, opts ...RenderOpt) beep.Streamer {
+	t := &traversalStreamer{
+		im: im,
+		ps: ps,
+		sr: sr,
+	}
+	for _, o := range opts {
+		o(t)
+	}
+
+	// Get the first pixel Streamer
+	ps.Reset(im.Bounds(), im, start)
+	t.next()
+
+	if t.maxDuration > 0 {
+		return beep.Take(sr.N(t.maxDuration), t)
+	}
+	return t
+}
+
+// sonify sets the current Streamer to the sonification of the current pixel.
+func (t *traversalStreamer) sonify() {
+	t.cur = api.SonifyAt(t.ps, t.im, t.loc, t.sr)
+	t.pixels++
+}
+
+// next traverses the PixelSound and sets up the next pixel Streamer, if there is one.
+func (t *traversalStreamer) next() {
+	if t.maxPixels > 0 && t.pixels >= t.maxPixels {
+		t.cur = nil
+		return
+	}
+	loc, ok := t.ps.Next()
+	if !ok {
+		t.cur = nil
+		return
+	}
+	t.loc = loc
+	t.sonify()
+}
+
+// Stream streams the pixel Streamers in traversal order until the traversal is finished.
+func (t *traversalStreamer) Stream(samples [][2]float64) (n int, ok bool) {
+	for n < len(samples) {
+		if t.cur == nil {
+			return n, n > 0
+		}
+		sn, sok := t.cur.Stream(samples[n:])
+		n += sn
+		if !sok {
+			if err := t.cur.Err(); err != nil && t.err == nil {
+				t.err = err
+			}
+			t.next()
+		}
+	}
+	return n, true
+}
+
+// Err returns the first error encountered by a pixel Streamer.
+func (t *traversalStreamer) Err() error {
+	return t.err
+}
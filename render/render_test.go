@@ -0,0 +1,249 @@
+package render
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+	"github.com/mewkiz/flac"
+	"github.com/mewkiz/flac/frame"
+	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/traversal"
+)
+
+// pixelSamples is the number of samples levelSonifier plays for each pixel.
+const pixelSamples = 10
+
+// levelSonifier plays every pixel as pixelSamples samples at a level of half its gray value.
+type levelSonifier struct{}
+
+func (levelSonifier) Sonify(c color.Color, sr beep.SampleRate) beep.Streamer {
+	return constant(pixelSamples, level(c))
+}
+
+// level returns the level levelSonifier plays a color at.
+func level(c color.Color) float64 {
+	return float64(color.GrayModel.Convert(c).(color.Gray).Y) / 255 / 2
+}
+
+// constant returns a Streamer of n samples at a level.
+func constant(n int, v float64) beep.Streamer {
+	return beep.Take(n, beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		for i := range samples {
+			samples[i] = [2]float64{v, v}
+		}
+		return len(samples), true
+	}))
+}
+
+// testImage returns a 3x2 image with a different gray for every pixel.
+func testImage() image.Image {
+	im := image.NewGray(image.Rect(0, 0, 3, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			im.SetGray(x, y, color.Gray{uint8(40 * (1 + x + 3*y))})
+		}
+	}
+	return im
+}
+
+// testPixelSound returns a PixelSound traversing rows with a levelSonifier.
+func testPixelSound(t *testing.T) api.PixelSound {
+	tr, err := traversal.New("TtoBLtoR", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &api.PixelSounder{Tr: tr, S: levelSonifier{}}
+}
+
+// wantLevels returns the levels of the left channel of the render of testImage.
+func wantLevels(im image.Image) []float64 {
+	var want []float64
+	b := im.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			for i := 0; i < pixelSamples; i++ {
+				want = append(want, level(im.At(x, y)))
+			}
+		}
+	}
+	return want
+}
+
+// drain returns the left channel of all of the samples of a Streamer.
+func drain(s beep.Streamer) []float64 {
+	var all []float64
+	buf := make([][2]float64, 7)
+	for {
+		n, ok := s.Stream(buf)
+		for _, v := range buf[:n] {
+			all = append(all, v[0])
+		}
+		if !ok {
+			return all
+		}
+	}
+}
+
+// decodeWAV returns the left channel of a WAV file. The samples are decoded with the Format of
+// the file, as the WAV decoder of beep scales 16 bit samples to half their range.
+func decodeWAV(t *testing.T, path string) []float64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, format, err := wav.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := bytes.Index(data, []byte("data"))
+	if i < 0 {
+		t.Fatal("no data chunk")
+	}
+	size := int(binary.LittleEndian.Uint32(data[i+4:]))
+	data = data[i+8 : i+8+size]
+	var all []float64
+	for len(data) > 0 {
+		sample, n := format.DecodeSigned(data)
+		all = append(all, sample[0])
+		data = data[n:]
+	}
+	return all
+}
+
+// decodeFLAC returns the left channel of a FLAC file, checking that every frame is verbatim.
+func decodeFLAC(t *testing.T, path string) []float64 {
+	stream, err := flac.ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	max := float64(int32(1)<<(stream.Info.BitsPerSample-1) - 1)
+	var all []float64
+	for {
+		f, err := stream.ParseNext()
+		if errors.Is(err, io.EOF) {
+			return all
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		for c, sub := range f.Subframes {
+			if sub.Pred != frame.PredVerbatim {
+				t.Errorf("subframe %d of frame %d is not verbatim", c, f.Num)
+			}
+		}
+		for _, v := range f.Subframes[0].Samples {
+			all = append(all, float64(v)/max)
+		}
+	}
+}
+
+// checkLevels checks that decoded samples match levels, up to the precision of 16 bit samples.
+func checkLevels(t *testing.T, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d samples, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1.0/(1<<15) {
+			t.Fatalf("sample %d is %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRenderFile(t *testing.T) {
+	im := testImage()
+	for _, ext := range []string{"wav", "flac"} {
+		t.Run(ext, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "render."+ext)
+			if err := RenderFile(path, im, testPixelSound(t), image.Point{}, 44100); err != nil {
+				t.Fatal(err)
+			}
+			var got []float64
+			if ext == "wav" {
+				got = decodeWAV(t, path)
+			} else {
+				got = decodeFLAC(t, path)
+			}
+			checkLevels(t, got, wantLevels(im))
+		})
+	}
+}
+
+func TestEncodeFLACFrames(t *testing.T) {
+	for _, n := range []int{5, 1024, 2048, flacBlockSize, flacBlockSize + 5, flacBlockSize + 4096} {
+		path := filepath.Join(t.TempDir(), "frames.flac")
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		format := beep.Format{SampleRate: 44100, NumChannels: 2, Precision: 2}
+		if err := Encode(f, constant(n, 0.25), format, FLAC); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+
+		// The last frame is padded with silence up to flacMinBlockSize samples
+		want := make([]float64, n)
+		for i := range want {
+			want[i] = 0.25
+		}
+		if last := n % flacBlockSize; last > 0 && last < flacMinBlockSize {
+			want = append(want, make([]float64, flacMinBlockSize-last)...)
+		}
+		checkLevels(t, decodeFLAC(t, path), want)
+	}
+}
+
+func TestRenderOpts(t *testing.T) {
+	im := testImage()
+	all := wantLevels(im)
+	tests := []struct {
+		name string
+		opts []RenderOpt
+		want []float64
+	}{
+		{"none", nil, all},
+		{"max pixels", []RenderOpt{WithMaxPixels(2)}, all[:2*pixelSamples]},
+		{"max duration", []RenderOpt{WithMaxDuration(25 * time.Millisecond)}, all[:25]},
+		{"max duration beyond the traversal", []RenderOpt{WithMaxDuration(time.Second)}, all},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStreamer(im, testPixelSound(t), image.Point{}, 1000, tt.opts...)
+			checkLevels(t, drain(s), tt.want)
+			if n, ok := s.Stream(make([][2]float64, 4)); n != 0 || ok {
+				t.Errorf("drained Streamer streamed %d samples, %v", n, ok)
+			}
+		})
+	}
+}
+
+func TestMix(t *testing.T) {
+	s := mix(constant(10, 0.5), constant(30, 0.25), constant(0, 1))
+	want := make([]float64, 30)
+	for i := range want {
+		want[i] = 0.25 / 3
+		if i < 10 {
+			want[i] += 0.5 / 3
+		}
+	}
+	checkLevels(t, drain(s), want)
+	if n, ok := s.Stream(make([][2]float64, 4)); n != 0 || ok {
+		t.Errorf("drained mix streamed %d samples, %v", n, ok)
+	}
+	if m := s.(*mixStreamer); len(m.streamers) != 0 {
+		t.Errorf("drained mix keeps %d Streamers", len(m.streamers))
+	}
+}
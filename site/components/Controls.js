@@ -1,7 +1,13 @@
+import Fidelity from "./Fidelity";
 import FileInput from "./FileInput";
 import Modes from "./modes/Modes";
 
-const Controls = ({ onImageChange, onAudioChange, onModeChange }) => {
+const Controls = ({
+  onImageChange,
+  onAudioChange,
+  onModeChange,
+  onFidelityChange,
+}) => {
   return (
     <div className="flex flex-col max-w-lg items-center mx-auto">
       <div className="flex flex-wrap truncate gap-4 p-3">
@@ -12,6 +18,7 @@ const Controls = ({ onImageChange, onAudioChange, onModeChange }) => {
           Select an audio file
         </FileInput>
       </div>
+      <Fidelity onChange={onFidelityChange}></Fidelity>
       {/* <Modes onChange={onModeChange}></Modes> */}
     </div>
   );
@@ -0,0 +1,20 @@
+const Fidelity = ({ onChange }) => {
+  return (
+    <div className="flex items-center gap-2 p-3">
+      <label htmlFor="fidelity" className="text-black">
+        Fidelity
+      </label>
+      <input
+        id="fidelity"
+        type="range"
+        min="1"
+        max="32"
+        defaultValue="32"
+        onChange={onChange}
+        className="accent-violet-600"
+      ></input>
+    </div>
+  );
+};
+
+export default Fidelity;
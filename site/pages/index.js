@@ -104,6 +104,12 @@ const Home = () => {
 
   const onModeChange = useCallback((e) => console.log(e.target), []);
 
+  const onFidelityChange = useCallback((e) => {
+    // Made available globally by golang code, the slider is reversed so
+    // that moving right plays smaller blocks of pixels
+    window.golangSetFidelity(33 - parseInt(e.target.value, 10));
+  }, []);
+
   return (
     <>
       <Script src="/pixelsound.js"></Script>
@@ -145,6 +151,7 @@ const Home = () => {
           onImageChange={onImageChange}
           onAudioChange={onAudioChange}
           onModeChange={onModeChange}
+          onFidelityChange={onFidelityChange}
         ></Controls>
       </div>
     </>
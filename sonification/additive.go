@@ -0,0 +1,152 @@
+package sonification
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/rytrose/pixelsound/util"
+)
+
+// Partials is a Streamer of a sum of sine waves at harmonics of a fundamental frequency.
+type Partials struct {
+	Freq   float64   // Fundamental frequency
+	Amps   []float64 // Amplitude of each harmonic, from the fundamental up, summing to at most 1
+	Amp    float64
+	SR     float64
+	phases *[]phasor // Phase of each harmonic, shared with the Partials returned by Note
+}
+
+// NewPartials is a Partials factory
+func NewPartials(freq float64, amps []float64, amp, sr float64) *Partials {
+	return &Partials{
+		Freq: freq,
+		Amps: amps,
+		Amp:  amp,
+		SR:   sr,
+	}
+}
+
+// Note returns Partials with other settings that continue from the phases of p, so that notes
+// played one after another join without clicks. p itself is unchanged.
+func (p *Partials) Note(freq float64, amps []float64, amp float64) *Partials {
+	if p.phases == nil {
+		p.phases = new([]phasor)
+	}
+	return &Partials{
+		Freq:   freq,
+		Amps:   amps,
+		Amp:    amp,
+		SR:     p.SR,
+		phases: p.phases,
+	}
+}
+
+// Stream returns samples of the sum of the harmonics, leaving out those above the Nyquist frequency.
+func (p *Partials) Stream(samples [][2]float64) (n int, ok bool) {
+	if p.phases == nil {
+		p.phases = new([]phasor)
+	}
+	phases := *p.phases
+	if len(phases) < len(p.Amps) {
+		phases = append(phases, make([]phasor, len(p.Amps)-len(phases))...)
+		*p.phases = phases
+	}
+	for i := range samples {
+		y := 0.0
+		for h, a := range p.Amps {
+			freq := p.Freq * float64(h+1)
+			phase := phases[h].next(freq, p.SR)
+			if a != 0 && freq < p.SR/2 {
+				y += math.Sin(2*math.Pi*phase) * a
+			}
+		}
+		samples[i][0] = y * p.Amp
+		samples[i][1] = y * p.Amp
+	}
+	return len(samples), true
+}
+
+// Err returns no error.
+func (p *Partials) Err() error {
+	return nil
+}
+
+// Additive is an ImageSonifier that plays a pixel and its neighbourhood of Size by Size pixels
+// as the harmonics of an additive synth. The nearer a neighbour is to the pixel, the lower its
+// harmonic, with the pixel itself as the fundamental, and the luminance of a neighbour is the
+// amplitude of its harmonic. The color of the pixel is mapped to the fundamental frequency,
+// duration, amplitude and pan of the note, by default hue to frequency. When the traversal
+// samples cells of pixels, such as blocks, the neighbours are the neighbouring cells.
+type Additive struct {
+	voice
+	offsets  []image.Point // Offset of the neighbour of each harmonic, from the fundamental up
+	partials *Partials     // Partials of the neighbourhood played last, continued by every note
+}
+
+// Defaults of an Additive.
+var (
+	DefaultAdditiveFreq     = Mapping{Channel: Hue, Min: 55, Max: 440, Curve: Exponential}
+	DefaultAdditiveDuration = Mapping{Channel: None, Min: 50, Max: 50}
+	DefaultAdditiveSize     = 3
+)
+
+// NewAdditive returns an Additive playing neighbourhoods of size by size pixels.
+func NewAdditive(sr beep.SampleRate, size int, opts ...VoiceOpt) *Additive {
+	if size < 1 {
+		size = 1
+	}
+	var offsets []image.Point
+	for dy := -(size - 1) / 2; dy <= size/2; dy++ {
+		for dx := -(size - 1) / 2; dx <= size/2; dx++ {
+			offsets = append(offsets, image.Point{dx, dy})
+		}
+	}
+	// Order neighbours by ring around the pixel, keeping rows top to bottom within a ring
+	sort.SliceStable(offsets, func(i, j int) bool {
+		return ring(offsets[i]) < ring(offsets[j])
+	})
+
+	amps := make([]float64, len(offsets))
+	amps[0] = 1
+	return &Additive{
+		voice:    newVoice(DefaultAdditiveFreq, DefaultAdditiveDuration, opts),
+		offsets:  offsets,
+		partials: NewPartials(440, amps, 1.0, float64(sr.N(1*time.Second))),
+	}
+}
+
+// ring returns the distance of an offset from the center of a neighbourhood, counted in square rings.
+func ring(p image.Point) int {
+	return int(math.Max(math.Abs(float64(p.X)), math.Abs(float64(p.Y))))
+}
+
+// SonifyAt plays the neighbourhood of the pixel at p at the pitch of its color.
+func (a *Additive) SonifyAt(img image.Image, p image.Point, sr beep.SampleRate) beep.Streamer {
+	// Neighbours outside of the image are silent
+	amps := make([]float64, len(a.offsets))
+	sum := 0.0
+	for h, o := range a.offsets {
+		if q := p.Add(o); q.In(img.Bounds()) {
+			amps[h] = util.Luminance(img.At(q.X, q.Y))
+			sum += amps[h]
+		}
+	}
+	if sum > 0 {
+		for h := range amps {
+			amps[h] /= sum
+		}
+	}
+	a.partials.Amps = amps
+	return a.Sonify(img.At(p.X, p.Y), sr)
+}
+
+// Sonify plays the neighbourhood played last, or a sine wave if there isn't one, at the pitch of a color.
+// Each note keeps its own neighbourhood and settings, so notes can be queued before they play.
+func (a *Additive) Sonify(c color.Color, sr beep.SampleRate) beep.Streamer {
+	freq, amp, n := a.note(c, sr)
+	return a.play(a.partials.Note(freq, a.partials.Amps, amp), c, n, sr)
+}
@@ -0,0 +1,67 @@
+package sonification
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/faiface/beep"
+	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/traversal"
+)
+
+func TestAdditiveSampled(t *testing.T) {
+	// The left block of 2 by 2 pixels is black and the right one is white
+	im := image.NewGray(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 2; x < 4; x++ {
+			im.SetGray(x, y, color.Gray{255})
+		}
+	}
+	tr, err := traversal.New("TtoBLtoR", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAdditive(44100, 3)
+	ps := &api.PixelSounder{Tr: traversal.Sampling{Block: 2}.Wrap(tr), S: a}
+	ps.Reset(im.Bounds(), im, image.Point{})
+	p, _ := ps.Next()
+	api.SonifyAt(ps, im, p, 44100)
+
+	// The white block is the right neighbour of the black one, rather than a black pixel
+	for h, o := range a.offsets {
+		want := 0.0
+		if o == (image.Point{1, 0}) {
+			want = 1
+		}
+		if a.partials.Amps[h] != want {
+			t.Errorf("amplitude of harmonic %d at offset %v is %v, want %v", h, o, a.partials.Amps[h], want)
+		}
+	}
+}
+
+func TestAdditiveQueued(t *testing.T) {
+	sr := beep.SampleRate(44100)
+	im := image.NewGray(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			im.SetGray(x, y, color.Gray{uint8((x + 4*y) * 15)})
+		}
+	}
+	points := []image.Point{{0, 0}, {2, 1}, {3, 3}}
+	queued := NewAdditive(sr, 3)
+	var notes []beep.Streamer
+	for _, p := range points {
+		notes = append(notes, queued.SonifyAt(im, p, sr))
+	}
+
+	// Notes queued before any plays sound like notes played as soon as they are made
+	played := NewAdditive(sr, 3)
+	for i, p := range points {
+		want := drain(played.SonifyAt(im, p, sr))
+		if got := drain(notes[i]); !reflect.DeepEqual(got, want) {
+			t.Errorf("queued note of %v differs from the note played at once", p)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package sonification
 
 import (
+	"fmt"
 	"image/color"
 	"io"
 
@@ -10,16 +11,31 @@ import (
 	"github.com/faiface/beep/vorbis"
 	"github.com/faiface/beep/wav"
 	"github.com/rytrose/pixelsound/api"
-	"github.com/rytrose/pixelsound/log"
 	"github.com/rytrose/pixelsound/util"
 )
 
 // resampleQuality determines the quality when resampling.
 const resampleQuality = 3
 
-// NewAudioScrubber returns a SonifyFunc that uses RGB to determine playback location, number of samples, and speed
-// of the provided audio buffer formatted with the provided extension.
-func NewAudioScrubber(r io.ReadCloser, ext string) api.SonifyFunc {
+// AudioScrubber is a Sonifier that uses RGB to determine playback location, number of samples, and speed
+// of an audio buffer. It emits a ProgressEvent with the playback location of every color it sonifies.
+type AudioScrubber struct {
+	api.Emitter
+	audioStreamer beep.StreamSeekCloser
+	env           ADSR // Envelope of each excerpt
+}
+
+type AudioScrubberOpt func(*AudioScrubber)
+
+// WithScrubEnvelope shapes each excerpt with an ADSR envelope.
+func WithScrubEnvelope(e ADSR) AudioScrubberOpt {
+	return func(a *AudioScrubber) {
+		a.env = e
+	}
+}
+
+// NewAudioScrubber returns an AudioScrubber of the provided audio buffer formatted with the provided extension.
+func NewAudioScrubber(r io.ReadCloser, ext string, opts ...AudioScrubberOpt) (*AudioScrubber, error) {
 	var audioStreamer beep.StreamSeekCloser
 	var err error
 	switch ext {
@@ -32,28 +48,33 @@ func NewAudioScrubber(r io.ReadCloser, ext string) api.SonifyFunc {
 	case "flac":
 		audioStreamer, _, err = flac.Decode(r)
 	default:
-		log.Printf("unable to decode audio file with extension %s", ext)
+		return nil, fmt.Errorf("unable to decode audio file with extension %s", ext)
 	}
 	if err != nil {
-		log.Printf("unable to decode audio: %s", err)
+		return nil, fmt.Errorf("unable to decode audio: %w", err)
 	}
 	// FIXME: will leak resources if beep.StreamSeekCloser actually needs to be closed
-	return func(c color.Color, sr beep.SampleRate, state interface{}) (beep.Streamer, interface{}) {
-		return audioScrubber(audioStreamer, c, sr, state)
+	a := &AudioScrubber{
+		audioStreamer: audioStreamer,
+		env:           ADSR{Sustain: 1},
+	}
+	for _, o := range opts {
+		o(a)
 	}
+	return a, nil
 }
 
-// audioScrubber uses RGB to determine audio buffer playback location, number of samples, and speed.
-func audioScrubber(audioStreamer beep.StreamSeekCloser, c color.Color, sr beep.SampleRate, state interface{}) (beep.Streamer, interface{}) {
+// Sonify uses RGB to determine audio buffer playback location, number of samples, and speed.
+func (a *AudioScrubber) Sonify(c color.Color, sr beep.SampleRate) beep.Streamer {
 	r, g, b, _ := util.FloatRGBA(c)
-	bufferLen := audioStreamer.Len()
+	bufferLen := a.audioStreamer.Len()
 	// G - duration
 	maxDurPercentage := 0.1
 	durSamples := int(maxDurPercentage * g * float64(bufferLen))
 	// R - location
 	startSample := int(r * float64(bufferLen-durSamples))
-	audioStreamer.Seek(startSample)
-	s := beep.Take(durSamples, audioStreamer)
+	a.audioStreamer.Seek(startSample)
+	s := beep.Take(durSamples, a.audioStreamer)
 	// B - speed (resampling ratio)
 	var ratio float64
 	if b < 0.5 {
@@ -63,14 +84,15 @@ func audioScrubber(audioStreamer beep.StreamSeekCloser, c color.Color, sr beep.S
 		// Slow down when more blue
 		ratio = 0.5 + (1 - b)
 	}
-	resampledStreamer := beep.ResampleRatio(resampleQuality, ratio, s)
-
-	updateWaveform, ok := state.(func(float64))
-	if ok {
-		updateWaveform(float64(startSample) / float64(bufferLen))
-	} else {
-		log.Println("state was not expected function:", state)
+	var resampledStreamer beep.Streamer = beep.ResampleRatio(resampleQuality, ratio, s)
+	if a.env.enabled() {
+		resampledStreamer = a.env.Wrap(resampledStreamer, int(float64(durSamples)/ratio), sr)
 	}
 
-	return resampledStreamer, state
+	a.Emit(api.Event{
+		Kind:     api.ProgressEvent,
+		Progress: float64(startSample) / float64(bufferLen),
+	})
+
+	return resampledStreamer
 }
@@ -0,0 +1,161 @@
+package sonification
+
+import (
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/rytrose/pixelsound/api"
+)
+
+// ADSR describes an attack, decay, sustain, release envelope that shapes the amplitude of a note.
+type ADSR struct {
+	Attack  time.Duration // Time to rise from silence to full amplitude
+	Decay   time.Duration // Time to fall from full amplitude to the sustain level
+	Sustain float64       // Amplitude held after the decay until the release, from 0 to 1
+	Release time.Duration // Time to fall from the sustain level to silence at the end of the note
+	Curve   Curve         // Shape of each segment
+}
+
+// enabled returns whether the ADSR changes the amplitude of a note at all.
+func (e ADSR) enabled() bool {
+	return e.Attack > 0 || e.Decay > 0 || e.Release > 0 || e.Sustain < 1
+}
+
+// Wrap shapes a Streamer of n samples with the ADSR, so that the release ends on the
+// last sample. If the envelope is longer than n samples, its segments are shortened
+// proportionally.
+func (e ADSR) Wrap(s beep.Streamer, n int, sr beep.SampleRate) beep.Streamer {
+	a, d, r := sr.N(e.Attack), sr.N(e.Decay), sr.N(e.Release)
+	if total := a + d + r; total > n {
+		scale := float64(n) / float64(total)
+		a = int(float64(a) * scale)
+		d = int(float64(d) * scale)
+		r = int(float64(r) * scale)
+	}
+	return &envelope{
+		s:       s,
+		attack:  a,
+		decay:   d,
+		sustain: e.Sustain,
+		release: n - r,
+		end:     n,
+		curve:   e.Curve,
+	}
+}
+
+// envelope is a Streamer shaped by an ADSR, with segment boundaries in samples.
+type envelope struct {
+	s       beep.Streamer
+	pos     int // Number of samples streamed
+	attack  int // Length of the attack
+	decay   int // Length of the decay
+	sustain float64
+	release int // Start of the release
+	end     int // End of the release
+	curve   Curve
+}
+
+// Stream streams the wrapped Streamer scaled by the envelope.
+func (e *envelope) Stream(samples [][2]float64) (n int, ok bool) {
+	n, ok = e.s.Stream(samples)
+	for i := range samples[:n] {
+		g := e.gain(e.pos)
+		samples[i][0] *= g
+		samples[i][1] *= g
+		e.pos++
+	}
+	return n, ok
+}
+
+// gain returns the amplitude of the envelope at a sample.
+func (e *envelope) gain(pos int) float64 {
+	switch {
+	case pos < e.attack:
+		return e.segment(float64(pos)/float64(e.attack), 0, 1)
+	case pos < e.attack+e.decay:
+		return e.segment(float64(pos-e.attack)/float64(e.decay), 1, e.sustain)
+	case pos < e.release:
+		return e.sustain
+	case pos < e.end:
+		return e.segment(float64(pos-e.release)/float64(e.end-e.release), e.sustain, 0)
+	}
+	return 0
+}
+
+// segment returns the amplitude at x from 0 to 1 along a segment from one level to another.
+// Falling segments are rising ones reversed in time, so that an exponential release drops
+// quickly and then tails off into silence, rather than holding and then cutting off.
+func (e *envelope) segment(x, from, to float64) float64 {
+	if to >= from {
+		return e.curve.Scale(x, from, to)
+	}
+	return to + (from-to)*e.curve.Scale(1-x, 0, 1)
+}
+
+// Err propagates the wrapped Streamer's errors.
+func (e *envelope) Err() error {
+	return e.s.Err()
+}
+
+// envelopeParams returns the parameters to configure an ADSR.
+func envelopeParams() api.Schema {
+	return api.Schema{
+		{
+			Name:        "attack",
+			Description: "envelope attack in milliseconds",
+			Kind:        api.FloatParam,
+			Default:     0.0,
+			Min:         0,
+			Max:         10000,
+		},
+		{
+			Name:        "decay",
+			Description: "envelope decay in milliseconds",
+			Kind:        api.FloatParam,
+			Default:     0.0,
+			Min:         0,
+			Max:         10000,
+		},
+		{
+			Name:        "sustain",
+			Description: "envelope sustain level",
+			Kind:        api.FloatParam,
+			Default:     1.0,
+			Min:         0,
+			Max:         1,
+		},
+		{
+			Name:        "release",
+			Description: "envelope release in milliseconds",
+			Kind:        api.FloatParam,
+			Default:     0.0,
+			Min:         0,
+			Max:         10000,
+		},
+		{
+			Name:        "envelope-curve",
+			Description: "curve of each envelope segment",
+			Kind:        api.StringParam,
+			Default:     Linear.String(),
+			Choices:     curveNames,
+		},
+	}
+}
+
+// envelopeFromParams returns the ADSR configured by parameters from envelopeParams.
+func envelopeFromParams(p api.Params) (ADSR, error) {
+	curve, err := ParseCurve(p.String("envelope-curve"))
+	if err != nil {
+		return ADSR{}, err
+	}
+	ms := func(name string) time.Duration {
+		return time.Duration(p.Float(name) * float64(time.Millisecond))
+	}
+	return ADSR{
+		Attack:  ms("attack"),
+		Decay:   ms("decay"),
+		Sustain: p.Float("sustain"),
+		Release: ms("release"),
+		Curve:   curve,
+	}, nil
+}
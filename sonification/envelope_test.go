@@ -0,0 +1,96 @@
+package sonification
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// TestEnvelopeSegments checks the shape of every segment of an ADSR: each starts at the level
+// the previous one ended at and moves steadily to its own level. Exponential segments change
+// fastest near their higher level, so that releases drop quickly and then tail off into silence,
+// logarithmic segments change fastest near their lower level, and linear segments evenly.
+func TestEnvelopeSegments(t *testing.T) {
+	const seg = 100 // Samples of each segment
+	sr := beep.SampleRate(1000)
+	for _, curve := range []Curve{Linear, Exponential, Logarithmic} {
+		for _, sustain := range []float64{0.5, 0} {
+			e := ADSR{
+				Attack:  100 * time.Millisecond,
+				Decay:   100 * time.Millisecond,
+				Sustain: sustain,
+				Release: 100 * time.Millisecond,
+				Curve:   curve,
+			}
+			ones := beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+				for i := range samples {
+					samples[i] = [2]float64{1, 1}
+				}
+				return len(samples), true
+			})
+			gains := make([][2]float64, 4*seg)
+			e.Wrap(ones, len(gains), sr).Stream(gains)
+
+			segments := []struct {
+				name     string
+				start    int
+				from, to float64
+			}{
+				{"attack", 0, 0, 1},
+				{"decay", seg, 1, sustain},
+				{"sustain", 2 * seg, sustain, sustain},
+				{"release", 3 * seg, sustain, 0},
+			}
+			for i, s := range segments {
+				g := make([]float64, seg+1)
+				for j := range g[:seg] {
+					g[j] = gains[s.start+j][0]
+				}
+				// The segment ends where the next one starts
+				g[seg] = 0
+				if i+1 < len(segments) {
+					g[seg] = gains[segments[i+1].start][0]
+				}
+
+				if g[0] != s.from {
+					t.Errorf("%v %s of sustain %v starts at %v, want %v", curve, s.name, sustain, g[0], s.from)
+				}
+				if math.Abs(g[seg]-s.to) > 1e-9 {
+					t.Errorf("%v %s of sustain %v ends at %v, want %v", curve, s.name, sustain, g[seg], s.to)
+				}
+				for j := 1; j <= seg; j++ {
+					if (g[j]-g[j-1])*(s.to-s.from) < 0 {
+						t.Fatalf("%v %s of sustain %v moves away from %v at sample %d", curve, s.name, sustain, s.to, j)
+					}
+				}
+				if s.from == s.to {
+					continue
+				}
+
+				// Compare the change over the tenth of the segment nearest its higher level with
+				// the tenth nearest its lower level
+				first, last := math.Abs(g[seg/10]-g[0]), math.Abs(g[seg]-g[seg-seg/10])
+				nearHigh, nearLow := first, last
+				if s.to > s.from {
+					nearHigh, nearLow = last, first
+				}
+				switch curve {
+				case Linear:
+					if math.Abs(nearHigh-nearLow) > 1e-9 {
+						t.Errorf("%v %s of sustain %v changes by %v near its higher level and %v near its lower level, want the same", curve, s.name, sustain, nearHigh, nearLow)
+					}
+				case Exponential:
+					if nearHigh <= nearLow {
+						t.Errorf("%v %s of sustain %v changes by %v near its higher level and %v near its lower level, want faster near its higher level", curve, s.name, sustain, nearHigh, nearLow)
+					}
+				case Logarithmic:
+					if nearHigh >= nearLow {
+						t.Errorf("%v %s of sustain %v changes by %v near its higher level and %v near its lower level, want faster near its lower level", curve, s.name, sustain, nearHigh, nearLow)
+					}
+				}
+			}
+		}
+	}
+}
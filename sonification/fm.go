@@ -0,0 +1,107 @@
+package sonification
+
+import (
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// FM is a Streamer of a sine wave carrier whose phase is modulated by a sine wave modulator.
+type FM struct {
+	Freq   float64 // Frequency of the carrier
+	Ratio  float64 // Frequency of the modulator relative to the carrier
+	Index  float64 // Modulation index, the peak phase deviation of the carrier in radians
+	Amp    float64
+	SR     float64
+	phases *fmPhases // Shared with the FMs returned by Note
+}
+
+// fmPhases are the phases of the carrier and modulator of an FM.
+type fmPhases struct {
+	carrier   phasor
+	modulator phasor
+}
+
+// NewFM is an FM factory
+func NewFM(freq, ratio, index, amp, sr float64) *FM {
+	return &FM{
+		Freq:  freq,
+		Ratio: ratio,
+		Index: index,
+		Amp:   amp,
+		SR:    sr,
+	}
+}
+
+// Note returns an FM with other settings that continues from the phases of f, so that notes
+// played one after another join without clicks. f itself is unchanged.
+func (f *FM) Note(freq, ratio, index, amp float64) *FM {
+	if f.phases == nil {
+		f.phases = &fmPhases{}
+	}
+	return &FM{
+		Freq:   freq,
+		Ratio:  ratio,
+		Index:  index,
+		Amp:    amp,
+		SR:     f.SR,
+		phases: f.phases,
+	}
+}
+
+// Stream returns samples of the frequency modulated wave.
+func (f *FM) Stream(samples [][2]float64) (n int, ok bool) {
+	if f.phases == nil {
+		f.phases = &fmPhases{}
+	}
+	for i := range samples {
+		m := math.Sin(2*math.Pi*f.phases.modulator.next(f.Freq*f.Ratio, f.SR)) * f.Index
+		y := math.Sin(2*math.Pi*f.phases.carrier.next(f.Freq, f.SR)+m) * f.Amp
+		samples[i][0] = y
+		samples[i][1] = y
+	}
+	return len(samples), true
+}
+
+// Err returns no error.
+func (f *FM) Err() error {
+	return nil
+}
+
+// FMColor is a Sonifier that maps channels of a color to an FM voice. By default, hue is
+// mapped to frequency, saturation to modulation index, and lightness to the ratio of the
+// modulator's frequency to the carrier's, so grays are pure sines and saturated colors are
+// bright and rich.
+type FMColor struct {
+	voice
+	index Mapping // Mapping to modulation index
+	ratio Mapping // Mapping to modulator frequency ratio
+	fm    *FM     // FM whose phases continue from note to note
+}
+
+// Default mappings of an FMColor.
+var (
+	DefaultFMColorFreq     = Mapping{Channel: Hue, Min: 110, Max: 880, Curve: Exponential}
+	DefaultFMColorDuration = Mapping{Channel: None, Min: 50, Max: 50}
+	DefaultFMColorIndex    = Mapping{Channel: Saturation, Min: 0, Max: 8}
+	DefaultFMColorRatio    = Mapping{Channel: Lightness, Min: 0.5, Max: 4}
+)
+
+// NewFMColor returns an FMColor with Mappings to modulation index and modulator frequency ratio.
+func NewFMColor(sr beep.SampleRate, index, ratio Mapping, opts ...VoiceOpt) *FMColor {
+	return &FMColor{
+		voice: newVoice(DefaultFMColorFreq, DefaultFMColorDuration, opts),
+		index: index,
+		ratio: ratio,
+		fm:    NewFM(440, 1, 0, 1.0, float64(sr.N(1*time.Second))),
+	}
+}
+
+// Sonify maps the channels of a color to an FM voice. Each note keeps its own settings, so
+// notes can be queued before they play.
+func (f *FMColor) Sonify(c color.Color, sr beep.SampleRate) beep.Streamer {
+	freq, amp, n := f.note(c, sr)
+	return f.play(f.fm.Note(freq, f.ratio.Map(c), f.index.Map(c), amp), c, n, sr)
+}
@@ -0,0 +1,47 @@
+package sonification
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/faiface/beep"
+)
+
+// drain returns all of the samples of a Streamer.
+func drain(s beep.Streamer) [][2]float64 {
+	var all [][2]float64
+	buf := make([][2]float64, 512)
+	for {
+		n, ok := s.Stream(buf)
+		all = append(all, buf[:n]...)
+		if !ok {
+			return all
+		}
+	}
+}
+
+// queueColors are colors played one after another, to check that queued notes keep their own settings.
+var queueColors = []color.Color{
+	color.RGBA{255, 0, 0, 255},
+	color.RGBA{0, 0, 255, 255},
+	color.RGBA{40, 200, 90, 255},
+}
+
+func TestFMColorQueued(t *testing.T) {
+	sr := beep.SampleRate(44100)
+	queued := NewFMColor(sr, DefaultFMColorIndex, DefaultFMColorRatio)
+	var notes []beep.Streamer
+	for _, c := range queueColors {
+		notes = append(notes, queued.Sonify(c, sr))
+	}
+
+	// Notes queued before any plays sound like notes played as soon as they are made
+	played := NewFMColor(sr, DefaultFMColorIndex, DefaultFMColorRatio)
+	for i, c := range queueColors {
+		want := drain(played.Sonify(c, sr))
+		if got := drain(notes[i]); !reflect.DeepEqual(got, want) {
+			t.Errorf("queued note %d of %v differs from the note played at once", i, c)
+		}
+	}
+}
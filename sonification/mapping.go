@@ -0,0 +1,152 @@
+package sonification
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/util"
+)
+
+// Channel is a component of a color that can be mapped to a sound.
+type Channel = util.Channel
+
+// Channels of a color that can be mapped to a sound.
+const (
+	None       = util.None // No channel, always maps to the minimum of a range
+	Red        = util.Red
+	Green      = util.Green
+	Blue       = util.Blue
+	Alpha      = util.Alpha
+	Hue        = util.Hue
+	Saturation = util.Saturation
+	Lightness  = util.Lightness
+	Luma       = util.Luma
+)
+
+// Curve shapes how a value from 0 to 1 is scaled into a range.
+type Curve int
+
+const (
+	Linear      Curve = iota // Evenly spaced across the range
+	Exponential              // Evenly spaced ratios across the range, e.g. octaves of frequency
+	Logarithmic              // The inverse of Exponential, changing fastest at the bottom of the range
+)
+
+// curveNames are the names of Curves, by Curve.
+var curveNames = []string{"linear", "exponential", "logarithmic"}
+
+// String returns the name of the Curve.
+func (c Curve) String() string {
+	if c < 0 || int(c) >= len(curveNames) {
+		return fmt.Sprintf("Curve(%d)", int(c))
+	}
+	return curveNames[c]
+}
+
+// ParseCurve returns the Curve with the provided name.
+func ParseCurve(s string) (Curve, error) {
+	for i, name := range curveNames {
+		if strings.EqualFold(s, name) {
+			return Curve(i), nil
+		}
+	}
+	return Linear, fmt.Errorf("no curve named %s", s)
+}
+
+// defaultCurveRatio is the ratio between the top and bottom of a range used to shape
+// exponential and logarithmic curves when the range has no ratio of its own.
+const defaultCurveRatio = 100
+
+// Scale scales x from 0 to 1 into the range from min to max.
+func (c Curve) Scale(x, min, max float64) float64 {
+	x = math.Max(0, math.Min(1, x))
+	if c == Linear || min == max {
+		return min + (max-min)*x
+	}
+	// Exponential ranges between positive bounds interpolate geometrically
+	ratio := float64(defaultCurveRatio)
+	if min > 0 && max > 0 {
+		ratio = max / min
+	}
+	var y float64
+	switch c {
+	case Exponential:
+		y = (math.Pow(ratio, x) - 1) / (ratio - 1)
+	case Logarithmic:
+		y = math.Log(1+(ratio-1)*x) / math.Log(ratio)
+	}
+	return min + (max-min)*y
+}
+
+// Mapping maps a Channel of a color into a range along a Curve.
+type Mapping struct {
+	Channel  Channel
+	Min, Max float64
+	Curve    Curve
+}
+
+// Map returns the value of a color's Channel scaled into the range of the Mapping.
+func (m Mapping) Map(c color.Color) float64 {
+	if m.Channel == None {
+		return m.Min
+	}
+	return m.Curve.Scale(m.Channel.Value(c), m.Min, m.Max)
+}
+
+// mappingParams returns the parameters to configure a Mapping to a target, such as "freq".
+// min and max bound the range of the Mapping.
+func mappingParams(target, description string, def Mapping, min, max float64) api.Schema {
+	return api.Schema{
+		{
+			Name:        target + "-channel",
+			Description: "color channel mapped to " + description,
+			Kind:        api.StringParam,
+			Default:     def.Channel.String(),
+			Choices:     util.ChannelNames(),
+		},
+		{
+			Name:        target + "-min",
+			Description: "minimum " + description,
+			Kind:        api.FloatParam,
+			Default:     def.Min,
+			Min:         min,
+			Max:         max,
+		},
+		{
+			Name:        target + "-max",
+			Description: "maximum " + description,
+			Kind:        api.FloatParam,
+			Default:     def.Max,
+			Min:         min,
+			Max:         max,
+		},
+		{
+			Name:        target + "-curve",
+			Description: "curve of the mapping to " + description,
+			Kind:        api.StringParam,
+			Default:     def.Curve.String(),
+			Choices:     curveNames,
+		},
+	}
+}
+
+// mappingFromParams returns the Mapping to a target configured by parameters from mappingParams.
+func mappingFromParams(p api.Params, target string) (Mapping, error) {
+	channel, err := util.ParseChannel(p.String(target + "-channel"))
+	if err != nil {
+		return Mapping{}, err
+	}
+	curve, err := ParseCurve(p.String(target + "-curve"))
+	if err != nil {
+		return Mapping{}, err
+	}
+	return Mapping{
+		Channel: channel,
+		Min:     p.Float(target + "-min"),
+		Max:     p.Float(target + "-max"),
+		Curve:   curve,
+	}, nil
+}
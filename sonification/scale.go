@@ -0,0 +1,171 @@
+package sonification
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rytrose/pixelsound/api"
+)
+
+// Quantizer snaps frequencies to pitches.
+type Quantizer interface {
+	// Quantize returns the pitch in Hz nearest to a frequency in Hz.
+	Quantize(freq float64) float64
+}
+
+// Scale is a Quantizer that snaps frequencies to the nearest pitch of a musical scale.
+type Scale struct {
+	Root  float64   // Frequency of the root in Hz
+	Cents []float64 // Pitches of the scale in cents above the root, repeated every octave
+}
+
+// Scales contains the pitches of common scales in cents above their root.
+var Scales = map[string][]float64{
+	"chromatic":        {0, 100, 200, 300, 400, 500, 600, 700, 800, 900, 1000, 1100},
+	"major":            {0, 200, 400, 500, 700, 900, 1100},
+	"minor":            {0, 200, 300, 500, 700, 800, 1000},
+	"harmonic-minor":   {0, 200, 300, 500, 700, 800, 1100},
+	"pentatonic":       {0, 200, 400, 700, 900},
+	"minor-pentatonic": {0, 300, 500, 700, 1000},
+	"whole-tone":       {0, 200, 400, 600, 800, 1000},
+}
+
+// NewScale returns a Scale with a root in Hz and pitches in cents above the root.
+// Pitches are wrapped into a single octave.
+func NewScale(root float64, cents ...float64) *Scale {
+	wrapped := make([]float64, 0, len(cents))
+	for _, c := range cents {
+		wrapped = append(wrapped, c-1200*math.Floor(c/1200))
+	}
+	sort.Float64s(wrapped)
+	return &Scale{
+		Root:  root,
+		Cents: wrapped,
+	}
+}
+
+// Quantize returns the pitch of the Scale nearest to a frequency.
+func (s *Scale) Quantize(freq float64) float64 {
+	if freq <= 0 || s.Root <= 0 || len(s.Cents) == 0 {
+		return freq
+	}
+	cents := 1200 * math.Log2(freq/s.Root)
+	octave := math.Floor(cents / 1200)
+	within := cents - 1200*octave
+
+	// Pitches of the octaves below and above are also candidates, e.g. for scales without the root
+	nearest := math.Inf(1)
+	for _, c := range s.Cents {
+		for _, candidate := range [...]float64{c - 1200, c, c + 1200} {
+			if math.Abs(within-candidate) < math.Abs(within-nearest) {
+				nearest = candidate
+			}
+		}
+	}
+	return s.Root * math.Pow(2, octave+nearest/1200)
+}
+
+// noteSemitones are the semitones of natural notes above C.
+var noteSemitones = map[byte]int{'C': 0, 'D': 2, 'E': 4, 'F': 5, 'G': 7, 'A': 9, 'B': 11}
+
+// NoteFrequency returns the frequency in Hz of a note name in scientific pitch notation,
+// such as "A4", "C#3" or "Bb2", where A4 is 440 Hz.
+func NoteFrequency(note string) (float64, error) {
+	if len(note) < 2 {
+		return 0, fmt.Errorf("invalid note %q", note)
+	}
+	semitone, ok := noteSemitones[strings.ToUpper(note[:1])[0]]
+	if !ok {
+		return 0, fmt.Errorf("invalid note %q", note)
+	}
+	rest := note[1:]
+	for len(rest) > 0 && (rest[0] == '#' || rest[0] == 'b') {
+		if rest[0] == '#' {
+			semitone++
+		} else {
+			semitone--
+		}
+		rest = rest[1:]
+	}
+	octave, err := strconv.Atoi(rest)
+	if err != nil {
+		return 0, fmt.Errorf("invalid octave of note %q", note)
+	}
+	// MIDI note 69 is A4
+	midi := 12*(octave+1) + semitone
+	return 440 * math.Pow(2, float64(midi-69)/12), nil
+}
+
+// scaleNames returns the sorted names of Scales.
+func scaleNames() []string {
+	names := make([]string, 0, len(Scales))
+	for name := range Scales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// quantizerParams returns the parameters to configure a Scale that pitches are quantized to.
+func quantizerParams() api.Schema {
+	return api.Schema{
+		{
+			Name:        "scale",
+			Description: "scale to quantize pitches to, none for continuous pitch, or custom for the pitches of cents",
+			Kind:        api.StringParam,
+			Default:     "none",
+			Choices:     append(append([]string{"none"}, scaleNames()...), "custom"),
+		},
+		{
+			Name:        "root",
+			Description: "root note of the scale, e.g. C4, F#3 or Bb2",
+			Kind:        api.StringParam,
+			Default:     "C4",
+		},
+		{
+			Name:        "cents",
+			Description: "comma separated pitches of a custom scale, in cents above the root",
+			Kind:        api.StringParam,
+			Default:     "",
+		},
+	}
+}
+
+// quantizerFromParams returns the Scale configured by parameters from quantizerParams,
+// or nil if pitches aren't quantized.
+func quantizerFromParams(p api.Params) (Quantizer, error) {
+	name := p.String("scale")
+	if name == "" || name == "none" {
+		return nil, nil
+	}
+	root, err := NoteFrequency(p.String("root"))
+	if err != nil {
+		return nil, err
+	}
+	if name != "custom" {
+		cents, ok := Scales[name]
+		if !ok {
+			return nil, fmt.Errorf("no scale named %s", name)
+		}
+		return NewScale(root, cents...), nil
+	}
+
+	var cents []float64
+	for _, s := range strings.Split(p.String("cents"), ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		c, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cents %q: %w", s, err)
+		}
+		cents = append(cents, c)
+	}
+	if len(cents) == 0 {
+		return nil, fmt.Errorf("custom scale requires cents")
+	}
+	return NewScale(root, cents...), nil
+}
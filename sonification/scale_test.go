@@ -0,0 +1,60 @@
+package sonification
+
+import (
+	"math"
+	"testing"
+)
+
+// centsAbove returns the frequency a number of cents above a root.
+func centsAbove(root, cents float64) float64 {
+	return root * math.Pow(2, cents/1200)
+}
+
+func TestScaleQuantize(t *testing.T) {
+	const root = 440
+	tests := []struct {
+		name  string
+		cents []float64
+		in    float64 // Cents above the root of the frequency to quantize
+		want  float64 // Cents above the root of the pitch it's quantized to
+	}{
+		{"root", Scales["major"], 0, 0},
+		{"nearest", Scales["major"], 260, 200},
+		{"next octave root", Scales["major"], 1160, 1200},
+		{"below root", Scales["major"], -30, 0},
+		{"octave below", Scales["major"], -1000, -1000},
+		{"previous octave without root", []float64{1100}, 50, -100},
+		{"next octave without root", []float64{100}, 1250, 1300},
+		{"unwrapped cents", []float64{-100, 1300}, 50, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewScale(root, tt.cents...).Quantize(centsAbove(root, tt.in))
+			if want := centsAbove(root, tt.want); math.Abs(got-want) > 1e-9 {
+				t.Errorf("Quantize(%v cents) = %v Hz, want %v Hz (%v cents)", tt.in, got, want, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoteFrequency(t *testing.T) {
+	tests := []struct {
+		note string
+		want float64
+	}{
+		{"A4", 440},
+		{"A3", 220},
+		{"C4", 261.6255653005986},
+		{"Bb2", 116.54094037952248},
+		{"C#3", 138.59131548843604},
+	}
+	for _, tt := range tests {
+		got, err := NoteFrequency(tt.note)
+		if err != nil {
+			t.Fatalf("NoteFrequency(%q) returned %v", tt.note, err)
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("NoteFrequency(%q) = %v, want %v", tt.note, got, tt.want)
+		}
+	}
+}
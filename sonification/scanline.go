@@ -0,0 +1,120 @@
+package sonification
+
+import (
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/rytrose/pixelsound/sonification/wavetable"
+	"github.com/rytrose/pixelsound/util"
+)
+
+// Scanline is an ImageSonifier that plays the row, or column, containing a pixel as a single
+// cycle of a waveform, with the luminance of each pixel as the amplitude of a sample. The color
+// of the pixel is mapped to the frequency, duration, amplitude and pan of the note, by default
+// lightness to frequency.
+type Scanline struct {
+	voice
+	columns bool                     // If set, plays columns instead of rows
+	img     image.Image              // Image the cached tables are lines of
+	tables  map[int]*wavetable.Table // Tables of the lines played last, by row or column
+	lines   []int                    // Lines of the cached tables, from least to most recently played
+	osc     *wavetable.Oscillator    // Oscillator of the line played last, continued by every note
+}
+
+// scanlineTables is the most tables of lines a Scanline keeps.
+const scanlineTables = 64
+
+// Default mappings of a Scanline.
+var (
+	DefaultScanlineFreq     = Mapping{Channel: Lightness, Min: 55, Max: 440, Curve: Exponential}
+	DefaultScanlineDuration = Mapping{Channel: None, Min: 50, Max: 50}
+)
+
+// NewScanline returns a Scanline, playing columns instead of rows if columns is set.
+func NewScanline(sr beep.SampleRate, columns bool, opts ...VoiceOpt) *Scanline {
+	return &Scanline{
+		voice:   newVoice(DefaultScanlineFreq, DefaultScanlineDuration, opts),
+		columns: columns,
+		osc:     wavetable.NewOscillator(wavetable.Sine, 440, 1.0, float64(sr.N(1*time.Second))),
+	}
+}
+
+// SonifyAt plays the line containing the pixel at p at the pitch of its color.
+func (s *Scanline) SonifyAt(img image.Image, p image.Point, sr beep.SampleRate) beep.Streamer {
+	line := p.Y
+	if s.columns {
+		line = p.X
+	}
+	if t := s.lineTable(img, line); t != nil {
+		s.osc.Wave = t
+	}
+	return s.Sonify(img.At(p.X, p.Y), sr)
+}
+
+// lineTable returns the Table of a line of an image, reusing the tables of the lines played
+// last and discarding the least recently played beyond scanlineTables.
+func (s *Scanline) lineTable(img image.Image, line int) *wavetable.Table {
+	if img != s.img {
+		s.img = img
+		s.tables = map[int]*wavetable.Table{}
+		s.lines = s.lines[:0]
+	}
+	t, ok := s.tables[line]
+	if ok {
+		for i, l := range s.lines {
+			if l == line {
+				s.lines = append(s.lines[:i], s.lines[i+1:]...)
+				break
+			}
+		}
+	} else {
+		if len(s.lines) >= scanlineTables {
+			delete(s.tables, s.lines[0])
+			s.lines = s.lines[1:]
+		}
+		t = s.table(img, line)
+		s.tables[line] = t
+	}
+	s.lines = append(s.lines, line)
+	return t
+}
+
+// Sonify plays the line played last, or a sine wave if there isn't one, at the pitch of a color.
+// Each note keeps its own line and settings, so notes can be queued before they play.
+func (s *Scanline) Sonify(c color.Color, sr beep.SampleRate) beep.Streamer {
+	freq, amp, n := s.note(c, sr)
+	return s.play(s.osc.Note(s.osc.Wave, freq, amp), c, n, sr)
+}
+
+// table returns the Table of a row or column of an image, without DC, or nil if the line is too short.
+func (s *Scanline) table(img image.Image, line int) *wavetable.Table {
+	b := img.Bounds()
+	var cycle []float64
+	if s.columns {
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			cycle = append(cycle, util.Luminance(img.At(line, y)))
+		}
+	} else {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			cycle = append(cycle, util.Luminance(img.At(x, line)))
+		}
+	}
+
+	// Center the cycle around 0, so that lines of flat color are silent
+	mean := 0.0
+	for _, v := range cycle {
+		mean += v
+	}
+	mean /= float64(len(cycle))
+	for i := range cycle {
+		cycle[i] -= mean
+	}
+
+	t, err := wavetable.NewTable(cycle)
+	if err != nil {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,57 @@
+package sonification
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/faiface/beep"
+)
+
+func TestScanlineTables(t *testing.T) {
+	s := NewScanline(44100, false)
+	im := image.NewGray(image.Rect(0, 0, 4, 2*scanlineTables))
+	for y := 0; y < 2*scanlineTables; y++ {
+		s.SonifyAt(im, image.Point{0, y}, 44100)
+	}
+	if len(s.tables) != scanlineTables || len(s.lines) != scanlineTables {
+		t.Fatalf("kept %d tables of %d lines, want %d", len(s.tables), len(s.lines), scanlineTables)
+	}
+
+	// Playing a cached line again keeps it over the least recently played one
+	first := s.lines[0]
+	kept := s.tables[first]
+	s.SonifyAt(im, image.Point{0, first}, 44100)
+	s.SonifyAt(im, image.Point{0, 0}, 44100)
+	if s.tables[first] != kept {
+		t.Errorf("table of line %d was rebuilt or discarded after being played again", first)
+	}
+	if _, ok := s.tables[first+1]; ok {
+		t.Errorf("table of line %d was kept, want it discarded as least recently played", first+1)
+	}
+}
+
+func TestScanlineQueued(t *testing.T) {
+	sr := beep.SampleRate(44100)
+	im := image.NewGray(image.Rect(0, 0, 8, 3))
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 8; x++ {
+			im.SetGray(x, y, color.Gray{uint8(x * (y + 1) * 10)})
+		}
+	}
+	queued := NewScanline(sr, false)
+	var notes []beep.Streamer
+	for y := 0; y < 3; y++ {
+		notes = append(notes, queued.SonifyAt(im, image.Point{7, y}, sr))
+	}
+
+	// Notes queued before any plays sound like notes played as soon as they are made
+	played := NewScanline(sr, false)
+	for y := 0; y < 3; y++ {
+		want := drain(played.SonifyAt(im, image.Point{7, y}, sr))
+		if got := drain(notes[y]); !reflect.DeepEqual(got, want) {
+			t.Errorf("queued note of row %d differs from the note played at once", y)
+		}
+	}
+}
@@ -4,11 +4,24 @@ import (
 	"math"
 )
 
+// phasor is the phase of an oscillator, from 0 to 1, which wraps around once per cycle.
+type phasor float64
+
+// next returns the phase, then advances it by a frequency at a sample rate.
+func (p *phasor) next(freq, sr float64) float64 {
+	phase := float64(*p)
+	*p += phasor(freq / sr)
+	if *p >= 1.0 || *p < 0 {
+		*p -= phasor(math.Floor(float64(*p)))
+	}
+	return phase
+}
+
 // Sine is a simple Sine wave Streamer.
 type Sine struct {
 	Freq  float64
 	Amp   float64
-	phase float64
+	phase phasor
 	SR    float64
 }
 
@@ -24,13 +37,9 @@ func NewSine(freq float64, amp float64, sr float64) *Sine {
 // Stream returns samples of the sine wave.
 func (s *Sine) Stream(samples [][2]float64) (n int, ok bool) {
 	for i := range samples {
-		if s.phase >= 1.0 {
-			s.phase -= 1.0
-		}
-		y := math.Sin(2*math.Pi*s.phase) * s.Amp
+		y := math.Sin(2*math.Pi*s.phase.next(s.Freq, s.SR)) * s.Amp
 		samples[i][0] = y
 		samples[i][1] = y
-		s.phase += (s.Freq / s.SR)
 	}
 	return len(samples), true
 }
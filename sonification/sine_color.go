@@ -5,24 +5,34 @@ import (
 	"time"
 
 	"github.com/faiface/beep"
-	"github.com/rytrose/pixelsound/api"
-	"github.com/rytrose/pixelsound/util"
 )
 
-// NewSineColor returns a SonifyFunc that maps red to freq and green to duration of
-// a sine wave.
-func NewSineColor(sr beep.SampleRate) api.SonifyFunc {
-	sine := NewSine(440, 1.0, float64(sr.N(1*time.Second)))
-	return func(c color.Color, sr beep.SampleRate, state interface{}) (beep.Streamer, interface{}) {
-		return sineColor(sine, c, sr, state)
-	}
+// SineColor is a Sonifier that maps channels of a color to the frequency, duration,
+// amplitude, and pan of a sine wave. By default, red is mapped to frequency and green
+// is mapped to duration.
+type SineColor struct {
+	voice
+	sine *Sine // Sine wave kept between colors so its phase is continuous
 }
 
-// sineColor maps red to freq and green to duration.
-func sineColor(sine *Sine, c color.Color, sr beep.SampleRate, state interface{}) (beep.Streamer, interface{}) {
-	r, g, _, _ := util.FloatRGBA(c)
-	sine.Freq = 30 + (1600 * r)
-	durMS := 10 + (40 * g)
+// Default mappings of a SineColor.
+var (
+	DefaultSineColorFreq     = Mapping{Channel: Red, Min: 30, Max: 1630}
+	DefaultSineColorDuration = Mapping{Channel: Green, Min: 10, Max: 50}
+)
+
+// NewSineColor returns a SineColor.
+func NewSineColor(sr beep.SampleRate, opts ...VoiceOpt) *SineColor {
+	return &SineColor{
+		voice: newVoice(DefaultSineColorFreq, DefaultSineColorDuration, opts),
+		sine:  NewSine(440, 1.0, float64(sr.N(1*time.Second))),
+	}
+}
 
-	return beep.Take(sr.N(time.Duration(durMS)*time.Millisecond), sine), nil
+// Sonify maps the channels of a color to a sine wave.
+func (s *SineColor) Sonify(c color.Color, sr beep.SampleRate) beep.Streamer {
+	freq, amp, n := s.note(c, sr)
+	s.sine.Freq = freq
+	s.sine.Amp = amp
+	return s.play(s.sine, c, n, sr)
 }
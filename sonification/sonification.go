@@ -1,7 +1,239 @@
 package sonification
 
-// SonifyFuncNames defines the names of all the sonification functions.
-var SonifyFuncNames = map[string]interface{}{
-	"SineColor":     nil,
-	"AudioScrubber": nil,
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/faiface/beep"
+	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/sonification/wavetable"
+	"github.com/rytrose/pixelsound/util"
+)
+
+// Sonification describes a Sonifier, so that it can be listed, described and
+// configured by any UI.
+type Sonification struct {
+	Name        string
+	Description string
+	Params      api.Schema                                              // Parameters accepted by New
+	New         func(beep.SampleRate, api.Params) (api.Sonifier, error) // Creates the Sonifier
+}
+
+var (
+	sonificationsMu sync.RWMutex
+	sonifications   = map[string]Sonification{}
+)
+
+// Register makes a sonification available by its name. Sonifications outside of this
+// package can be registered from an init function. Panics if the name is already registered.
+func Register(s Sonification) {
+	sonificationsMu.Lock()
+	defer sonificationsMu.Unlock()
+	if _, ok := sonifications[s.Name]; ok {
+		panic(fmt.Sprintf("sonification %s registered twice", s.Name))
+	}
+	sonifications[s.Name] = s
+}
+
+// Lookup returns the registered sonification with the provided name.
+func Lookup(name string) (Sonification, bool) {
+	sonificationsMu.RLock()
+	defer sonificationsMu.RUnlock()
+	s, ok := sonifications[name]
+	return s, ok
+}
+
+// Names returns the sorted names of all registered sonifications.
+func Names() []string {
+	sonificationsMu.RLock()
+	defer sonificationsMu.RUnlock()
+	names := make([]string, 0, len(sonifications))
+	for name := range sonifications {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the registered Sonifier with the provided name, configured by parameter
+// values parsed from strings.
+func New(name string, sr beep.SampleRate, values map[string]string) (api.Sonifier, error) {
+	s, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("no sonification function named %s", name)
+	}
+	params, err := s.Params.Parse(values)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	son, err := s.New(sr, params)
+	if err != nil {
+		// The Sonifier didn't take ownership of any audio
+		params.Close()
+		return nil, err
+	}
+	return son, nil
+}
+
+func init() {
+	Register(Sonification{
+		Name:        "SineColor",
+		Description: "Plays a sine wave with color channels mapped to frequency, duration, amplitude and pan, by default red to frequency and green to duration.",
+		Params:      voiceParams(DefaultSineColorFreq, DefaultSineColorDuration),
+		New:         newSineColor,
+	})
+	Register(Sonification{
+		Name:        "FMColor",
+		Description: "Plays an FM voice with color channels mapped to frequency, modulation index and modulator ratio, by default hue to frequency, saturation to index and lightness to ratio.",
+		Params:      fmColorParams(),
+		New:         newFMColor,
+	})
+	Register(Sonification{
+		Name:        "WaveColor",
+		Description: "Plays a band-limited wavetable oscillator with a color channel choosing its waveform, by default hue to frequency and lightness choosing between sine, triangle, square and saw. With the Regions traversal, the color of each region chooses the waveform.",
+		Params:      waveColorParams(),
+		New:         newWaveColor,
+	})
+	Register(Sonification{
+		Name:        "Scanline",
+		Description: "Plays the row or column containing a pixel as a single cycle of a waveform, with luminance as amplitude, at a pitch mapped from the pixel's color, by default lightness to frequency.",
+		Params: append(voiceParams(DefaultScanlineFreq, DefaultScanlineDuration), api.Param{
+			Name:        "axis",
+			Description: "lines of the image played as waveforms",
+			Kind:        api.StringParam,
+			Default:     "rows",
+			Choices:     []string{"rows", "columns"},
+		}),
+		New: func(sr beep.SampleRate, p api.Params) (api.Sonifier, error) {
+			opts, err := voiceOptsFromParams(p)
+			if err != nil {
+				return nil, err
+			}
+			return NewScanline(sr, p.String("axis") == "columns", opts...), nil
+		},
+	})
+	Register(Sonification{
+		Name:        "Additive",
+		Description: "Plays a pixel and its neighbourhood as the harmonics of an additive synth, nearer neighbours as lower harmonics with luminance as amplitude, at a pitch mapped from the pixel's color, by default hue to frequency.",
+		Params: append(voiceParams(DefaultAdditiveFreq, DefaultAdditiveDuration), api.Param{
+			Name:        "size",
+			Description: "width of the neighbourhood of pixels played as harmonics",
+			Kind:        api.IntParam,
+			Default:     DefaultAdditiveSize,
+			Min:         1,
+			Max:         9,
+		}),
+		New: func(sr beep.SampleRate, p api.Params) (api.Sonifier, error) {
+			opts, err := voiceOptsFromParams(p)
+			if err != nil {
+				return nil, err
+			}
+			return NewAdditive(sr, p.Int("size"), opts...), nil
+		},
+	})
+	Register(Sonification{
+		Name:        "AudioScrubber",
+		Description: "Plays an excerpt of an audio file, with red mapped to location, green mapped to duration and blue mapped to speed.",
+		Params: append(api.Schema{
+			{
+				Name:        "audio",
+				Description: "audio file to scrub (mp3, wav, ogg or flac)",
+				Kind:        api.AudioParam,
+			},
+		}, envelopeParams()...),
+		New: func(sr beep.SampleRate, p api.Params) (api.Sonifier, error) {
+			audio := p.Audio("audio")
+			if audio.R == nil {
+				return nil, fmt.Errorf("AudioScrubber requires audio")
+			}
+			env, err := envelopeFromParams(p)
+			if err != nil {
+				return nil, err
+			}
+			return NewAudioScrubber(audio.R, audio.Ext, WithScrubEnvelope(env))
+		},
+	})
+}
+
+// fmColorParams returns the parameters of an FMColor.
+func fmColorParams() api.Schema {
+	params := voiceParams(DefaultFMColorFreq, DefaultFMColorDuration)
+	params = append(params, mappingParams("index", "modulation index", DefaultFMColorIndex, 0, 100)...)
+	params = append(params, mappingParams("ratio", "modulator to carrier frequency ratio", DefaultFMColorRatio, 0, 32)...)
+	return params
+}
+
+// newFMColor creates an FMColor configured by the parameters from fmColorParams.
+func newFMColor(sr beep.SampleRate, p api.Params) (api.Sonifier, error) {
+	opts, err := voiceOptsFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	index, err := mappingFromParams(p, "index")
+	if err != nil {
+		return nil, err
+	}
+	ratio, err := mappingFromParams(p, "ratio")
+	if err != nil {
+		return nil, err
+	}
+	return NewFMColor(sr, index, ratio, opts...), nil
+}
+
+// waveColorParams returns the parameters of a WaveColor.
+func waveColorParams() api.Schema {
+	params := voiceParams(DefaultWaveColorFreq, DefaultWaveColorDuration)
+	params = append(params, api.Schema{
+		{
+			Name:        "wave-channel",
+			Description: "color channel choosing the wave",
+			Kind:        api.StringParam,
+			Default:     DefaultWaveColorChannel.String(),
+			Choices:     util.ChannelNames(),
+		},
+		{
+			Name:        "waves",
+			Description: "comma separated waves chosen between, from " + waveNames(),
+			Kind:        api.StringParam,
+			Default:     strings.Join(DefaultWaveColorWaves, ","),
+		},
+	}...)
+	return params
+}
+
+// waveNames describes the names of the waves wavetable.NewWave can create.
+func waveNames() string {
+	return strings.Join(wavetable.WaveNames(), ", ") + ", or " + wavetable.FilePrefix + "path.wav for a WAV file of a single cycle"
+}
+
+// newWaveColor creates a WaveColor configured by the parameters from waveColorParams.
+func newWaveColor(sr beep.SampleRate, p api.Params) (api.Sonifier, error) {
+	opts, err := voiceOptsFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := util.ParseChannel(p.String("wave-channel"))
+	if err != nil {
+		return nil, err
+	}
+	var waves []wavetable.Wave
+	for _, name := range strings.Split(p.String("waves"), ",") {
+		w, err := wavetable.NewWave(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		waves = append(waves, w)
+	}
+	return NewWaveColor(sr, channel, waves, opts...), nil
+}
+
+// newSineColor creates a SineColor configured by the parameters from voiceParams.
+func newSineColor(sr beep.SampleRate, p api.Params) (api.Sonifier, error) {
+	opts, err := voiceOptsFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	return NewSineColor(sr, opts...), nil
 }
@@ -0,0 +1,148 @@
+package sonification
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/effects"
+	"github.com/rytrose/pixelsound/api"
+)
+
+// voice holds the settings shared by Sonifiers that play a pitched note for each color.
+type voice struct {
+	freq     Mapping   // Mapping to frequency in Hz
+	duration Mapping   // Mapping to duration in milliseconds
+	amp      Mapping   // Mapping to amplitude, from 0 to 1
+	pan      Mapping   // Mapping to pan, from -1 (left) to 1 (right)
+	q        Quantizer // If set, quantizes frequencies to pitches
+	env      ADSR      // Envelope of each note
+}
+
+type VoiceOpt func(*voice)
+
+// WithFreq sets the Mapping of a color to frequency in Hz.
+func WithFreq(m Mapping) VoiceOpt {
+	return func(v *voice) {
+		v.freq = m
+	}
+}
+
+// WithDuration sets the Mapping of a color to duration in milliseconds.
+func WithDuration(m Mapping) VoiceOpt {
+	return func(v *voice) {
+		v.duration = m
+	}
+}
+
+// WithAmp sets the Mapping of a color to amplitude, from 0 to 1.
+func WithAmp(m Mapping) VoiceOpt {
+	return func(v *voice) {
+		v.amp = m
+	}
+}
+
+// WithPan sets the Mapping of a color to pan, from -1 (left) to 1 (right).
+func WithPan(m Mapping) VoiceOpt {
+	return func(v *voice) {
+		v.pan = m
+	}
+}
+
+// WithQuantizer quantizes frequencies to pitches, such as those of a Scale.
+func WithQuantizer(q Quantizer) VoiceOpt {
+	return func(v *voice) {
+		v.q = q
+	}
+}
+
+// WithEnvelope shapes each note with an ADSR envelope.
+func WithEnvelope(e ADSR) VoiceOpt {
+	return func(v *voice) {
+		v.env = e
+	}
+}
+
+// newVoice returns a voice with default mappings, configured by options.
+func newVoice(freq, duration Mapping, opts []VoiceOpt) voice {
+	v := voice{
+		freq:     freq,
+		duration: duration,
+		amp:      Mapping{Channel: None, Min: 1, Max: 1},
+		pan:      Mapping{Channel: None, Min: 0, Max: 0},
+		env:      ADSR{Sustain: 1},
+	}
+	for _, o := range opts {
+		o(&v)
+	}
+	return v
+}
+
+// note returns the frequency, amplitude, and number of samples of the note for a color.
+func (v *voice) note(c color.Color, sr beep.SampleRate) (freq, amp float64, n int) {
+	freq = v.freq.Map(c)
+	if v.q != nil {
+		freq = v.q.Quantize(freq)
+	}
+	amp = v.amp.Map(c)
+	n = sr.N(time.Duration(v.duration.Map(c) * float64(time.Millisecond)))
+	return freq, amp, n
+}
+
+// play takes n samples of a Streamer as the note for a color, shaped by the envelope and panned.
+func (v *voice) play(s beep.Streamer, c color.Color, n int, sr beep.SampleRate) beep.Streamer {
+	s = beep.Take(n, s)
+	if v.env.enabled() {
+		s = v.env.Wrap(s, n, sr)
+	}
+	if pan := v.pan.Map(c); pan != 0 {
+		s = &effects.Pan{
+			Streamer: s,
+			Pan:      pan,
+		}
+	}
+	return s
+}
+
+// voiceParams returns the parameters to configure a voice, with default mappings to
+// frequency and duration.
+func voiceParams(freq, duration Mapping) api.Schema {
+	var params api.Schema
+	params = append(params, mappingParams("freq", "frequency in Hz", freq, 0, 20000)...)
+	params = append(params, mappingParams("duration", "duration in milliseconds", duration, 0, 10000)...)
+	params = append(params, mappingParams("amp", "amplitude", Mapping{Channel: None, Min: 1, Max: 1}, 0, 1)...)
+	params = append(params, mappingParams("pan", "pan from left to right", Mapping{Channel: None, Min: 0, Max: 0}, -1, 1)...)
+	params = append(params, quantizerParams()...)
+	params = append(params, envelopeParams()...)
+	return params
+}
+
+// voiceOptsFromParams returns the options configured by parameters from voiceParams.
+func voiceOptsFromParams(p api.Params) ([]VoiceOpt, error) {
+	var opts []VoiceOpt
+	for target, opt := range map[string]func(Mapping) VoiceOpt{
+		"freq":     WithFreq,
+		"duration": WithDuration,
+		"amp":      WithAmp,
+		"pan":      WithPan,
+	} {
+		m, err := mappingFromParams(p, target)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt(m))
+	}
+	q, err := quantizerFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	if q != nil {
+		opts = append(opts, WithQuantizer(q))
+	}
+	env, err := envelopeFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, WithEnvelope(env))
+	return opts, nil
+}
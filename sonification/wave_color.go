@@ -0,0 +1,69 @@
+package sonification
+
+import (
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/sonification/wavetable"
+)
+
+// WaveColor is a Sonifier that plays a wavetable oscillator, with a channel of a color
+// choosing its waveform. By default, hue is mapped to frequency and lightness chooses
+// between sine, triangle, square and saw waves, from dark to light. When the traversal
+// reports regions, the color of each region chooses the waveform for all of its pixels.
+type WaveColor struct {
+	voice
+	channel Channel               // Channel choosing the wave
+	waves   []wavetable.Wave      // Waves chosen between, evenly across the channel
+	region  wavetable.Wave        // Wave of the current region, if the traversal reports regions
+	osc     *wavetable.Oscillator // Oscillator whose phase continues from note to note
+}
+
+// Defaults of a WaveColor.
+var (
+	DefaultWaveColorFreq     = Mapping{Channel: Hue, Min: 110, Max: 880, Curve: Exponential}
+	DefaultWaveColorDuration = Mapping{Channel: None, Min: 50, Max: 50}
+	DefaultWaveColorChannel  = Lightness
+	DefaultWaveColorWaves    = []string{"sine", "triangle", "square", "saw"}
+)
+
+// NewWaveColor returns a WaveColor choosing between waves by a channel.
+func NewWaveColor(sr beep.SampleRate, channel Channel, waves []wavetable.Wave, opts ...VoiceOpt) *WaveColor {
+	return &WaveColor{
+		voice:   newVoice(DefaultWaveColorFreq, DefaultWaveColorDuration, opts),
+		channel: channel,
+		waves:   waves,
+		osc:     wavetable.NewOscillator(wavetable.Sine, 440, 1.0, float64(sr.N(1*time.Second))),
+	}
+}
+
+// HandleEvent chooses the wave of a region when the traversal enters it.
+func (w *WaveColor) HandleEvent(e api.Event) {
+	if e.Kind == api.RegionEvent && e.Color != nil {
+		w.region = w.wave(e.Color)
+	}
+}
+
+// wave returns the wave chosen by the channel of a color, or nil if there are no waves.
+func (w *WaveColor) wave(c color.Color) wavetable.Wave {
+	if len(w.waves) == 0 {
+		return nil
+	}
+	i := int(w.channel.Value(c) * float64(len(w.waves)))
+	return w.waves[int(math.Min(float64(i), float64(len(w.waves)-1)))]
+}
+
+// Sonify maps the channels of a color to a wave. Each note keeps its own settings, so notes
+// can be queued before they play.
+func (w *WaveColor) Sonify(c color.Color, sr beep.SampleRate) beep.Streamer {
+	freq, amp, n := w.note(c, sr)
+	if wave := w.region; wave != nil {
+		w.osc.Wave = wave
+	} else if wave := w.wave(c); wave != nil {
+		w.osc.Wave = wave
+	}
+	return w.play(w.osc.Note(w.osc.Wave, freq, amp), c, n, sr)
+}
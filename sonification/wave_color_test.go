@@ -0,0 +1,28 @@
+package sonification
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/faiface/beep"
+	"github.com/rytrose/pixelsound/sonification/wavetable"
+)
+
+func TestWaveColorQueued(t *testing.T) {
+	sr := beep.SampleRate(44100)
+	waves := []wavetable.Wave{wavetable.Sine, wavetable.Saw}
+	queued := NewWaveColor(sr, DefaultWaveColorChannel, waves)
+	var notes []beep.Streamer
+	for _, c := range queueColors {
+		notes = append(notes, queued.Sonify(c, sr))
+	}
+
+	// Notes queued before any plays sound like notes played as soon as they are made
+	played := NewWaveColor(sr, DefaultWaveColorChannel, waves)
+	for i, c := range queueColors {
+		want := drain(played.Sonify(c, sr))
+		if got := drain(notes[i]); !reflect.DeepEqual(got, want) {
+			t.Errorf("queued note %d of %v differs from the note played at once", i, c)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package wavetable
+
+import "math"
+
+// Oscillator is a Streamer playing a Wave. Its phase is kept when its Wave, frequency or
+// amplitude change, and shared with the Oscillators returned by Note, so that consecutive
+// notes join without clicks.
+type Oscillator struct {
+	Wave  Wave
+	Freq  float64
+	Amp   float64
+	SR    float64
+	phase *float64
+}
+
+// NewOscillator is an Oscillator factory
+func NewOscillator(wave Wave, freq, amp, sr float64) *Oscillator {
+	return &Oscillator{
+		Wave: wave,
+		Freq: freq,
+		Amp:  amp,
+		SR:   sr,
+	}
+}
+
+// Note returns an Oscillator playing a Wave at a frequency and amplitude, which continues from
+// the phase of o. o itself is unchanged, so notes can be queued before they play.
+func (o *Oscillator) Note(wave Wave, freq, amp float64) *Oscillator {
+	if o.phase == nil {
+		o.phase = new(float64)
+	}
+	return &Oscillator{
+		Wave:  wave,
+		Freq:  freq,
+		Amp:   amp,
+		SR:    o.SR,
+		phase: o.phase,
+	}
+}
+
+// Stream returns samples of the Wave.
+func (o *Oscillator) Stream(samples [][2]float64) (n int, ok bool) {
+	if o.phase == nil {
+		o.phase = new(float64)
+	}
+	phase := o.phase
+	for i := range samples {
+		y := o.Wave.Sample(*phase, o.Freq, o.SR) * o.Amp
+		samples[i][0] = y
+		samples[i][1] = y
+		*phase += o.Freq / o.SR
+		*phase -= math.Floor(*phase)
+	}
+	return len(samples), true
+}
+
+// Err returns no error.
+func (o *Oscillator) Err() error {
+	return nil
+}
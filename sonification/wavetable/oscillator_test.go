@@ -0,0 +1,31 @@
+package wavetable
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOscillatorPhase(t *testing.T) {
+	const sr = 44100.0
+	o := NewOscillator(Sine, 440, 1, sr)
+	freqs := []float64{440, 1000, 97, 3000}
+	buf := make([][2]float64, 300)
+	phase := 0.0
+	for i, freq := range freqs {
+		// Changing the frequency, directly or through a new note, continues from the same phase
+		s := o
+		if i%2 == 0 {
+			o.Freq = freq
+		} else {
+			s = o.Note(Sine, freq, 1)
+		}
+		s.Stream(buf)
+		for j, v := range buf {
+			if want := math.Sin(2 * math.Pi * phase); math.Abs(v[0]-want) > 1e-3 {
+				t.Fatalf("sample %d at %gHz is %v, want %v", j, freq, v[0], want)
+			}
+			phase += freq / sr
+			phase -= math.Floor(phase)
+		}
+	}
+}
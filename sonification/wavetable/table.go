@@ -0,0 +1,181 @@
+// Package wavetable provides band-limited wavetable oscillators.
+package wavetable
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/faiface/beep/wav"
+	"github.com/rytrose/pixelsound/util"
+)
+
+// TableSize is the number of samples in a single cycle of every level of a Table.
+const TableSize = 2048
+
+// levels is the number of band-limited levels of a Table, each with half the harmonics of the
+// one before, from TableSize/2 harmonics down to 1.
+const levels = 11
+
+// Table is a single-cycle waveform, band-limited at levels of one octave apart so that it
+// can be played at any frequency without aliasing.
+type Table struct {
+	spectrum []complex128      // Spectrum of the cycle, normalized
+	levels   [levels][]float64 // Cycles with at most TableSize/2 >> level harmonics
+	once     [levels]sync.Once // Band-limits each level once, when first played
+}
+
+// NewTable returns a Table from a single cycle of a waveform, such as one drawn or sampled by
+// a user, which is resampled to TableSize. The Table is normalized to a peak of 1.
+func NewTable(cycle []float64) (*Table, error) {
+	if len(cycle) < 2 {
+		return nil, fmt.Errorf("a table needs at least 2 samples, got %d", len(cycle))
+	}
+	x := make([]complex128, TableSize)
+	for i := range x {
+		// Linear interpolation of the cycle, wrapping around at the end
+		pos := float64(i) * float64(len(cycle)) / TableSize
+		j := int(pos)
+		frac := pos - float64(j)
+		x[i] = complex(cycle[j]*(1-frac)+cycle[(j+1)%len(cycle)]*frac, 0)
+	}
+	return newTableFromSpectrum(util.FFT(x)), nil
+}
+
+// maxCycle is the most samples of a cycle read by ReadTable, far more than any single cycle
+// at an audio sample rate.
+const maxCycle = 1 << 16
+
+// ReadTable returns a Table from a WAV file containing a single cycle of a waveform, with its
+// channels mixed down.
+func ReadTable(r io.Reader) (*Table, error) {
+	s, _, err := wav.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode cycle: %w", err)
+	}
+	var cycle []float64
+	buf := make([][2]float64, 512)
+	for {
+		n, ok := s.Stream(buf)
+		for _, sample := range buf[:n] {
+			cycle = append(cycle, (sample[0]+sample[1])/2)
+		}
+		if len(cycle) > maxCycle {
+			return nil, fmt.Errorf("a cycle has at most %d samples", maxCycle)
+		}
+		if !ok {
+			break
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("unable to decode cycle: %w", err)
+	}
+	return NewTable(cycle)
+}
+
+// newTableFromHarmonics returns a Table whose harmonic h has amplitude amp(h) in sine phase,
+// normalized to a peak of 1 so that the ringing of band-limiting doesn't clip.
+func newTableFromHarmonics(amp func(h int) float64) *Table {
+	spectrum := make([]complex128, TableSize)
+	for h := 1; h < TableSize/2; h++ {
+		// A sine of amplitude a has coefficients of -ia/2 and ia/2 at its positive and negative frequencies
+		a := amp(h) * TableSize / 2
+		spectrum[h] = complex(0, -a)
+		spectrum[TableSize-h] = complex(0, a)
+	}
+	return newTableFromSpectrum(spectrum)
+}
+
+// newTableFromSpectrum returns a Table with the levels of a spectrum of TableSize bins,
+// normalized to a peak of 1. Only the first level is band-limited, the others are when first played.
+func newTableFromSpectrum(spectrum []complex128) *Table {
+	t := &Table{spectrum: spectrum}
+	peak := 0.0
+	for _, v := range t.cycle(0) {
+		peak = math.Max(peak, math.Abs(v))
+	}
+	if peak > 0 {
+		for i := range spectrum {
+			spectrum[i] /= complex(peak, 0)
+		}
+		for i := range t.levels[0] {
+			t.levels[0][i] /= peak
+		}
+	}
+	return t
+}
+
+// cycle returns the cycle of a level, band-limiting the spectrum the first time it's needed.
+func (t *Table) cycle(level int) []float64 {
+	t.once[level].Do(func() {
+		maxHarmonic := TableSize / 2 >> level
+		bins := make([]complex128, TableSize)
+		bins[0] = t.spectrum[0]
+		for h := 1; h <= maxHarmonic && h < TableSize/2; h++ {
+			bins[h] = t.spectrum[h]
+			bins[TableSize-h] = t.spectrum[TableSize-h]
+		}
+		cycle := make([]float64, TableSize)
+		for i, v := range util.IFFT(bins) {
+			cycle[i] = real(v)
+		}
+		t.levels[level] = cycle
+	})
+	return t.levels[level]
+}
+
+// Sample returns the value of the waveform at a phase from 0 to 1, band-limited for a frequency
+// at a sample rate.
+func (t *Table) Sample(phase, freq, sr float64) float64 {
+	cycle := t.cycle(level(freq, sr))
+	pos := phase * TableSize
+	i := int(pos)
+	frac := pos - float64(i)
+	i %= TableSize
+	return cycle[i]*(1-frac) + cycle[(i+1)%TableSize]*frac
+}
+
+// level returns the level with the most harmonics that all stay below the Nyquist frequency.
+func level(freq, sr float64) int {
+	freq = math.Abs(freq)
+	if freq <= 0 {
+		return 0
+	}
+	// The highest harmonic of a level must be below sr/2, i.e. (TableSize/2 >> level) * freq < sr/2
+	maxHarmonics := sr / 2 / freq
+	l := int(math.Ceil(math.Log2(TableSize / 2 / maxHarmonics)))
+	if l < 0 {
+		return 0
+	}
+	if l >= levels {
+		return levels - 1
+	}
+	return l
+}
+
+// Standard waveforms, from -1 to 1.
+var (
+	Sine = newTableFromHarmonics(func(h int) float64 {
+		if h == 1 {
+			return 1
+		}
+		return 0
+	})
+	// Saw ramps up from 0 to 1, jumps to -1 half way through the cycle, then ramps up to 0.
+	Saw = newTableFromHarmonics(func(h int) float64 {
+		return -2 / (math.Pi * float64(h)) * math.Pow(-1, float64(h))
+	})
+	Square = newTableFromHarmonics(func(h int) float64 {
+		if h%2 == 0 {
+			return 0
+		}
+		return 4 / (math.Pi * float64(h))
+	})
+	Triangle = newTableFromHarmonics(func(h int) float64 {
+		if h%2 == 0 {
+			return 0
+		}
+		return 8 / (math.Pi * math.Pi * float64(h*h)) * math.Pow(-1, float64((h-1)/2))
+	})
+)
@@ -0,0 +1,96 @@
+package wavetable
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+)
+
+// writeCycle writes a WAV file of a single cycle of a sine wave with n samples.
+func writeCycle(t *testing.T, n int) string {
+	path := filepath.Join(t.TempDir(), "cycle.wav")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	i := 0
+	s := beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		if i >= n {
+			return 0, false
+		}
+		j := 0
+		for ; j < len(samples) && i < n; j, i = j+1, i+1 {
+			y := math.Sin(2 * math.Pi * float64(i) / float64(n))
+			samples[j] = [2]float64{y, y}
+		}
+		return j, true
+	})
+	format := beep.Format{SampleRate: 44100, NumChannels: 2, Precision: 2}
+	if err := wav.Encode(f, s, format); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewWaveFile(t *testing.T) {
+	w, err := NewWave(FilePrefix + writeCycle(t, 600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for phase := 0.0; phase < 1; phase += 0.01 {
+		got, want := w.Sample(phase, 110, 44100), Sine.Sample(phase, 110, 44100)
+		if math.Abs(got-want) > 0.01 {
+			t.Fatalf("sample at phase %v is %v, want %v", phase, got, want)
+		}
+	}
+}
+
+func TestNewWaveMissingFile(t *testing.T) {
+	if _, err := NewWave(FilePrefix + filepath.Join(t.TempDir(), "missing.wav")); err == nil {
+		t.Error("NewWave of a missing file returned no error")
+	}
+}
+
+func TestLevel(t *testing.T) {
+	const sr = 44100.0
+	tests := []struct {
+		freq float64
+		want int
+	}{
+		{0, 0},
+		{10, 0},
+		{sr / TableSize, 0},
+		{sr / TableSize * 1.01, 1},
+		{440, 5},
+		{-440, 5},
+		{4000, 8},
+		{sr / 4, 9},
+		{sr / 4 * 1.01, 10},
+		{sr / 2, 10},
+		{sr, levels - 1},
+	}
+	for _, tt := range tests {
+		if got := level(tt.freq, sr); got != tt.want {
+			t.Errorf("level of %gHz is %d, want %d", tt.freq, got, tt.want)
+		}
+	}
+
+	// Every level keeps all harmonics below the Nyquist frequency, and the level before would not
+	for freq := 1.0; freq < sr/2; freq *= 1.1 {
+		l := level(freq, sr)
+		if harmonics := TableSize / 2 >> l; float64(harmonics)*freq > sr/2 {
+			t.Fatalf("level %d of %gHz has %d harmonics, the highest above the Nyquist frequency", l, freq, harmonics)
+		}
+		if l == 0 {
+			continue
+		}
+		if prev := TableSize / 2 >> (l - 1); float64(prev)*freq <= sr/2 {
+			t.Fatalf("level %d of %gHz has fewer harmonics than level %d, which stays below the Nyquist frequency", l, freq, l-1)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package wavetable
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPulse(t *testing.T) {
+	const sr = 44100.0
+	// The ringing of each level is highest at the highest frequency it's played at
+	for l := 0; l < levels; l++ {
+		harmonics := TableSize / 2 >> l
+		freq := sr / 2 / float64(harmonics)
+		for _, w := range []float64{0.01, 0.1, 0.25, 0.426, 0.455, 0.5, 0.75, 0.9, 0.99} {
+			p := &Pulse{Width: w}
+			n := TableSize * 4
+			mean, peak := 0.0, 0.0
+			for i := 0; i < n; i++ {
+				v := p.Sample(float64(i)/float64(n), freq, sr)
+				mean += v / float64(n)
+				peak = math.Max(peak, math.Abs(v))
+			}
+			if math.Abs(mean) > 1e-3 {
+				t.Errorf("pulse of width %g at %gHz has DC %g, want 0", w, freq, mean)
+			}
+			if peak > 1 {
+				t.Errorf("pulse of width %g at %gHz peaks at %g, want at most 1", w, freq, peak)
+			}
+		}
+	}
+}
+
+func TestNewWaveNoise(t *testing.T) {
+	for _, name := range []string{"white", "pink"} {
+		a, err := NewWave(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := NewWave(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		same := true
+		for i := 0; i < 16; i++ {
+			if a.Sample(0, 440, 44100) != b.Sample(0, 440, 44100) {
+				same = false
+			}
+		}
+		if same {
+			t.Errorf("two %s noises play the same samples", name)
+		}
+	}
+}
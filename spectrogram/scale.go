@@ -0,0 +1,58 @@
+package spectrogram
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Scale is how frequencies are spaced along the height of an image.
+type Scale int
+
+const (
+	Linear Scale = iota // Evenly spaced frequencies
+	Log                 // Evenly spaced ratios of frequencies, e.g. octaves
+	Mel                 // Evenly spaced pitches as perceived, linear at low frequencies and logarithmic at high frequencies
+)
+
+// scaleNames are the names of Scales, by Scale.
+var scaleNames = []string{"linear", "log", "mel"}
+
+// ScaleNames returns the names of the Scales.
+func ScaleNames() []string {
+	return append([]string(nil), scaleNames...)
+}
+
+// String returns the name of the Scale.
+func (s Scale) String() string {
+	if s < 0 || int(s) >= len(scaleNames) {
+		return fmt.Sprintf("Scale(%d)", int(s))
+	}
+	return scaleNames[s]
+}
+
+// ParseScale returns the Scale with the provided name.
+func ParseScale(s string) (Scale, error) {
+	for i, name := range scaleNames {
+		if strings.EqualFold(s, name) {
+			return Scale(i), nil
+		}
+	}
+	return Linear, fmt.Errorf("no scale named %s", s)
+}
+
+// Position returns where a frequency lies in the range from min to max, from 0 to 1.
+func (s Scale) Position(freq, min, max float64) float64 {
+	switch s {
+	case Log:
+		return math.Log(freq/min) / math.Log(max/min)
+	case Mel:
+		return (mel(freq) - mel(min)) / (mel(max) - mel(min))
+	}
+	return (freq - min) / (max - min)
+}
+
+// mel returns the pitch of a frequency on the mel scale.
+func mel(freq float64) float64 {
+	return 2595 * math.Log10(1+freq/700)
+}
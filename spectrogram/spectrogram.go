@@ -0,0 +1,192 @@
+// Package spectrogram plays an image as a whole as a spectrogram, rather than one pixel at a time.
+package spectrogram
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/rytrose/pixelsound/render"
+	"github.com/rytrose/pixelsound/util"
+)
+
+// Spectrogram configures how an image is played as a magnitude spectrogram, with x as time,
+// y as frequency increasing from the bottom up, and the luminance of pixels as amplitude.
+// The zero value plays an image over 5 seconds, from 20Hz to the Nyquist frequency on a
+// linear scale, with random phases.
+type Spectrogram struct {
+	Scale      Scale         // Spacing of frequencies along the height of the image
+	MinFreq    float64       // Frequency of the bottom row in Hz, 20 if not positive
+	MaxFreq    float64       // Frequency of the top row in Hz, the Nyquist frequency if not positive or above it
+	Duration   time.Duration // Duration of the audio, 5 seconds if not positive
+	FFTSize    int           // Number of samples in each frame, a power of two, 2048 if not positive
+	Iterations int           // Number of iterations of Griffin-Lim phase reconstruction, or random phases if not positive
+	Seed       int64         // Seed of the random initial phases
+}
+
+// withDefaults returns the Spectrogram with defaults in place of unset fields.
+func (s Spectrogram) withDefaults(sr beep.SampleRate) Spectrogram {
+	if s.MinFreq <= 0 {
+		s.MinFreq = 20
+	}
+	if nyquist := float64(sr) / 2; s.MaxFreq <= 0 || s.MaxFreq > nyquist {
+		s.MaxFreq = nyquist
+	}
+	if s.Duration <= 0 {
+		s.Duration = 5 * time.Second
+	}
+	if s.FFTSize <= 0 {
+		s.FFTSize = 2048
+	}
+	return s
+}
+
+// Synthesize returns a Streamer playing an image as a spectrogram. The audio is synthesized
+// by an inverse short-time Fourier transform, with phases reconstructed by Griffin-Lim.
+func (s Spectrogram) Synthesize(im image.Image, sr beep.SampleRate) (beep.Streamer, error) {
+	s = s.withDefaults(sr)
+	if im.Bounds().Empty() {
+		return nil, fmt.Errorf("unable to synthesize an empty image")
+	}
+	if n := s.FFTSize; n < 4 || n&(n-1) != 0 {
+		return nil, fmt.Errorf("FFT size %d must be a power of two of at least 4", n)
+	}
+	if s.MinFreq >= s.MaxFreq {
+		return nil, fmt.Errorf("minimum frequency %g must be below maximum frequency %g", s.MinFreq, s.MaxFreq)
+	}
+
+	// Frames overlap by 75%, the minimum for Hann windows to reconstruct evenly
+	n := s.FFTSize
+	hop := n / 4
+	length := sr.N(s.Duration)
+	frames := (length+hop-1)/hop + 1
+	mags := s.magnitudes(im, sr, frames)
+	window := hann(n)
+
+	// Start from random phases, then alternate between the spectrum of the signal and the magnitudes
+	r := rand.New(rand.NewSource(s.Seed))
+	spec := make([][]complex128, frames)
+	for f := range spec {
+		spec[f] = make([]complex128, n/2+1)
+		for k := range spec[f] {
+			spec[f][k] = cmplx.Rect(mags[f][k], 2*math.Pi*r.Float64())
+		}
+	}
+	y := istft(spec, window, hop)
+	for i := 0; i < s.Iterations; i++ {
+		est := stft(y, window, hop, frames)
+		for f := range spec {
+			for k := range spec[f] {
+				spec[f][k] = cmplx.Rect(mags[f][k], cmplx.Phase(est[f][k]))
+			}
+		}
+		y = istft(spec, window, hop)
+	}
+
+	// Frames are centered on multiples of hop, so the audio starts half a frame in
+	y = y[n/2 : n/2+length]
+	peak := 0.0
+	for _, v := range y {
+		peak = math.Max(peak, math.Abs(v))
+	}
+	if peak > 0 {
+		for i := range y {
+			y[i] /= peak
+		}
+	}
+	return &samples{y: y}, nil
+}
+
+// Render plays an image as a spectrogram, writing the resulting audio to w in the provided Format.
+func (s Spectrogram) Render(w io.WriteSeeker, f render.Format, im image.Image, sr beep.SampleRate) error {
+	st, err := s.Synthesize(im, sr)
+	if err != nil {
+		return err
+	}
+	format := beep.Format{
+		SampleRate:  sr,
+		NumChannels: 2,
+		Precision:   2,
+	}
+	return render.Encode(w, st, format, f)
+}
+
+// magnitudes returns the magnitude of each frequency bin of each frame, interpolated from the
+// luminance of the pixels of an image.
+func (s Spectrogram) magnitudes(im image.Image, sr beep.SampleRate, frames int) [][]float64 {
+	b := im.Bounds()
+	lum := make([][]float64, b.Dy())
+	for y := range lum {
+		lum[y] = make([]float64, b.Dx())
+		for x := range lum[y] {
+			lum[y][x] = util.Luminance(im.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+
+	n := s.FFTSize
+	mags := make([][]float64, frames)
+	for f := range mags {
+		mags[f] = make([]float64, n/2+1)
+		x := 0.0
+		if frames > 1 {
+			x = float64(f) * float64(b.Dx()-1) / float64(frames-1)
+		}
+		for k := range mags[f] {
+			freq := float64(k) * float64(sr) / float64(n)
+			if freq < s.MinFreq || freq > s.MaxFreq {
+				continue
+			}
+			// Rows are counted from the top, so the highest frequency is the first row
+			y := (1 - s.Scale.Position(freq, s.MinFreq, s.MaxFreq)) * float64(b.Dy()-1)
+			mags[f][k] = bilinear(lum, x, y)
+		}
+	}
+	return mags
+}
+
+// bilinear returns the value of a grid at a point between its cells, interpolating linearly
+// between the four cells around it.
+func bilinear(grid [][]float64, x, y float64) float64 {
+	x0, y0 := int(x), int(y)
+	x1, y1 := x0+1, y0+1
+	if x1 >= len(grid[0]) {
+		x1 = x0
+	}
+	if y1 >= len(grid) {
+		y1 = y0
+	}
+	fx, fy := x-float64(x0), y-float64(y0)
+	top := grid[y0][x0]*(1-fx) + grid[y0][x1]*fx
+	bottom := grid[y1][x0]*(1-fx) + grid[y1][x1]*fx
+	return top*(1-fy) + bottom*fy
+}
+
+// samples is a Streamer of mono samples, played on both channels.
+type samples struct {
+	y   []float64
+	pos int
+}
+
+// Stream streams the samples until there are none left.
+func (s *samples) Stream(samples [][2]float64) (n int, ok bool) {
+	if s.pos >= len(s.y) {
+		return 0, false
+	}
+	for n < len(samples) && s.pos < len(s.y) {
+		samples[n][0] = s.y[s.pos]
+		samples[n][1] = s.y[s.pos]
+		n++
+		s.pos++
+	}
+	return n, true
+}
+
+// Err returns no error.
+func (s *samples) Err() error {
+	return nil
+}
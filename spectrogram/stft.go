@@ -0,0 +1,60 @@
+package spectrogram
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/rytrose/pixelsound/util"
+)
+
+// hann returns a periodic Hann window of n samples.
+func hann(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n))
+	}
+	return w
+}
+
+// stft returns the first len(window)/2+1 frequency bins of frames of a signal, windowed and
+// starting every hop samples.
+func stft(y, window []float64, hop, frames int) [][]complex128 {
+	n := len(window)
+	spec := make([][]complex128, frames)
+	x := make([]complex128, n)
+	for f := range spec {
+		for i := range x {
+			x[i] = complex(y[f*hop+i]*window[i], 0)
+		}
+		spec[f] = util.FFT(x)[:n/2+1]
+	}
+	return spec
+}
+
+// istft returns the signal of frames of frequency bins starting every hop samples, overlapped
+// and added with a window, and normalized by the overlapping windows.
+func istft(spec [][]complex128, window []float64, hop int) []float64 {
+	n := len(window)
+	y := make([]float64, (len(spec)-1)*hop+n)
+	norm := make([]float64, len(y))
+	x := make([]complex128, n)
+	for f, bins := range spec {
+		// The spectrum of a real signal is symmetric, with the conjugates of the positive frequencies as the negative ones
+		for k, v := range bins {
+			x[k] = v
+			if k > 0 && k < n/2 {
+				x[n-k] = cmplx.Conj(v)
+			}
+		}
+		for i, v := range util.IFFT(x) {
+			y[f*hop+i] += real(v) * window[i]
+			norm[f*hop+i] += window[i] * window[i]
+		}
+	}
+	for i := range y {
+		if norm[i] > 1e-8 {
+			y[i] /= norm[i]
+		}
+	}
+	return y
+}
@@ -0,0 +1,65 @@
+package spectrogram
+
+import (
+	"image"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+func TestSTFTReconstruction(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{4, 64, 2048} {
+		// Frames overlap by 75%, as in Synthesize
+		hop := n / 4
+		frames := 20
+		y := make([]float64, (frames-1)*hop+n)
+		for i := range y {
+			y[i] = r.Float64()*2 - 1
+		}
+		got := istft(stft(y, hann(n), hop, frames), hann(n), hop)
+		if len(got) != len(y) {
+			t.Fatalf("reconstructed %d samples from %d with a %d sample window, want %d", len(got), len(y), n, len(y))
+		}
+		// Samples are reconstructed at least half a frame from either end, the part Synthesize keeps
+		for i := n / 2; i < len(y)-n/2; i++ {
+			if math.Abs(got[i]-y[i]) > 1e-9 {
+				t.Fatalf("sample %d reconstructed with a %d sample window is %v, want %v", i, n, got[i], y[i])
+			}
+		}
+	}
+}
+
+func TestSynthesizeLength(t *testing.T) {
+	im := image.NewGray(image.Rect(0, 0, 8, 8))
+	for i := range im.Pix {
+		im.Pix[i] = uint8(i * 4)
+	}
+	sr := beep.SampleRate(8000)
+	for _, s := range []Spectrogram{
+		{Duration: time.Second},
+		{Duration: 1234 * time.Millisecond, FFTSize: 256},
+		{Duration: time.Millisecond, FFTSize: 4},
+		{Duration: 100 * time.Millisecond, FFTSize: 64, Iterations: 3, Scale: Mel},
+	} {
+		st, err := s.Synthesize(im, sr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		buf := make([][2]float64, 1000)
+		for {
+			sn, ok := st.Stream(buf)
+			n += sn
+			if !ok {
+				break
+			}
+		}
+		if want := sr.N(s.Duration); n != want {
+			t.Errorf("synthesized %d samples for %v with FFT size %d, want %d", n, s.Duration, s.FFTSize, want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package traversal
+
+import (
+	"image"
+	"math"
+
+	"github.com/rytrose/pixelsound/util"
+)
+
+// Contour is a Traverser that detects edges in the image with a Sobel operator and walks along
+// them, jumping to the next edge when one ends. Only pixels on edges are visited.
+type Contour struct {
+	Threshold float64 // Minimum gradient magnitude of an edge, relative to the strongest edge, from 0 to 1
+	Thin      bool    // If set, edges are thinned to the pixels of locally maximal gradient
+
+	order []image.Point // Pixels of the current traversal in order
+	i     int           // Index of the next pixel of the current traversal
+}
+
+// neighbours are the offsets of the 8 neighbours of a pixel, sides before corners so that
+// walks prefer to step rather than cut corners.
+var neighbours = []image.Point{{1, 0}, {0, 1}, {-1, 0}, {0, -1}, {1, 1}, {-1, 1}, {-1, -1}, {1, -1}}
+
+// Reset detects the edges of the image and plans a walk along them.
+func (c *Contour) Reset(bounds image.Rectangle, img image.Image, start image.Point) {
+	w, h := bounds.Dx(), bounds.Dy()
+	c.order = nil
+	c.i = 0
+	if w <= 0 || h <= 0 {
+		return
+	}
+	edges := c.edges(bounds, img)
+
+	visited := make([]bool, w*h)
+	index := func(p image.Point) int {
+		return (p.Y-bounds.Min.Y)*w + p.X - bounds.Min.X
+	}
+	for i := range edges {
+		if !edges[i] || visited[i] {
+			continue
+		}
+		// Follow the contour from this pixel until it runs out of unvisited edge pixels
+		p := image.Point{bounds.Min.X + i%w, bounds.Min.Y + i/w}
+		for {
+			visited[index(p)] = true
+			c.order = append(c.order, p)
+			next, found := p, false
+			for _, d := range neighbours {
+				n := p.Add(d)
+				if n.In(bounds) && edges[index(n)] && !visited[index(n)] {
+					next, found = n, true
+					break
+				}
+			}
+			if !found {
+				break
+			}
+			p = next
+		}
+	}
+}
+
+// edges returns whether each pixel within bounds, in rows, is on an edge.
+func (c *Contour) edges(bounds image.Rectangle, img image.Image) []bool {
+	w, h := bounds.Dx(), bounds.Dy()
+	lum := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			lum[y*w+x] = util.Luminance(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	// Pixels beyond the bounds repeat the nearest pixel within them
+	at := func(x, y int) float64 {
+		x = clamp(x, 0, w-1)
+		y = clamp(y, 0, h-1)
+		return lum[y*w+x]
+	}
+
+	mag := make([]float64, w*h)
+	dir := make([]int, w*h) // Gradient direction quantized to 0°, 45°, 90° or 135°
+	max := 0.0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			gx := at(x+1, y-1) + 2*at(x+1, y) + at(x+1, y+1) - at(x-1, y-1) - 2*at(x-1, y) - at(x-1, y+1)
+			gy := at(x-1, y+1) + 2*at(x, y+1) + at(x+1, y+1) - at(x-1, y-1) - 2*at(x, y-1) - at(x+1, y-1)
+			m := math.Hypot(gx, gy)
+			mag[y*w+x] = m
+			max = math.Max(max, m)
+			angle := math.Atan2(gy, gx) * 180 / math.Pi
+			if angle < 0 {
+				angle += 180
+			}
+			dir[y*w+x] = int(math.Round(angle/45)) % 4
+		}
+	}
+
+	edges := make([]bool, w*h)
+	if max == 0 {
+		return edges
+	}
+	// Pixels along the gradient on either side of a pixel, by quantized direction
+	across := [4]image.Point{{1, 0}, {1, 1}, {0, 1}, {-1, 1}}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			m := mag[y*w+x]
+			if m == 0 || m < c.Threshold*max {
+				continue
+			}
+			if c.Thin {
+				d := across[dir[y*w+x]]
+				ax, ay := clamp(x+d.X, 0, w-1), clamp(y+d.Y, 0, h-1)
+				bx, by := clamp(x-d.X, 0, w-1), clamp(y-d.Y, 0, h-1)
+				// Ties keep only the pixel behind, so that a step between two colors is one line
+				if m < mag[ay*w+ax] || m <= mag[by*w+bx] && (bx != x || by != y) {
+					continue
+				}
+			}
+			edges[y*w+x] = true
+		}
+	}
+	return edges
+}
+
+// Next returns the next pixel along the contours.
+func (c *Contour) Next() (image.Point, bool) {
+	if c.i >= len(c.order) {
+		return image.Point{}, false
+	}
+	c.i++
+	return c.order[c.i-1], true
+}
+
+// clamp returns x limited to the range from min to max.
+func clamp(x, min, max int) int {
+	if x < min {
+		return min
+	}
+	if x > max {
+		return max
+	}
+	return x
+}
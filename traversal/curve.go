@@ -0,0 +1,231 @@
+package traversal
+
+import (
+	"image"
+)
+
+// curve is a space-filling curve, which visits every point of an n by n square.
+type curve struct {
+	side func(size int) int        // Returns the side of the smallest square covering size
+	d2xy func(n, d int) (x, y int) // Returns the point at an index along the curve
+	xy2d func(n, x, y int) (d int) // Returns the index of a point along the curve
+}
+
+// traverse follows the curve from prev to the next point within bounds. The curve covers the
+// smallest square containing bounds, and points outside of bounds are skipped, so any bounds
+// can be traversed.
+func (c curve) traverse(prev image.Point, bounds image.Rectangle) (image.Point, bool) {
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= 0 || h <= 0 {
+		return prev, false
+	}
+	size := w
+	if h > size {
+		size = h
+	}
+	n := c.side(size)
+
+	// Points outside of bounds start from the beginning of the curve
+	d := -1
+	if prev.In(bounds) {
+		p := prev.Sub(bounds.Min)
+		d = c.xy2d(n, p.X, p.Y)
+	}
+
+	next, d, ok := c.nextIn(n, d, w, h)
+	if !ok {
+		return prev, false
+	}
+	// Look ahead to find whether next is the last point within bounds
+	_, _, more := c.nextIn(n, d, w, h)
+	return next.Add(bounds.Min), more
+}
+
+// nextIn returns the first point after index d along the curve that is within w by h,
+// along with its index.
+func (c curve) nextIn(n, d, w, h int) (image.Point, int, bool) {
+	for d++; d < n*n; d++ {
+		x, y := c.d2xy(n, d)
+		if x < w && y < h {
+			return image.Point{x, y}, d, true
+		}
+	}
+	return image.Point{}, d, false
+}
+
+// powerAtLeast returns the smallest power of base that is at least size.
+func powerAtLeast(base, size int) int {
+	n := 1
+	for n < size {
+		n *= base
+	}
+	return n
+}
+
+var hilbert = curve{
+	side: func(size int) int { return powerAtLeast(2, size) },
+	d2xy: hilbertD2XY,
+	xy2d: hilbertXY2D,
+}
+
+// Hilbert traverses an image along a Hilbert curve, so that consecutive pixels are always
+// neighbours within square power-of-two bounds.
+func Hilbert(prev image.Point, bounds image.Rectangle) (image.Point, bool) {
+	return hilbert.traverse(prev, bounds)
+}
+
+// hilbertD2XY returns the point at index d along a Hilbert curve covering an n by n square,
+// where n is a power of two.
+func hilbertD2XY(n, d int) (x, y int) {
+	for s := 1; s < n; s *= 2 {
+		rx := 1 & (d / 2)
+		ry := 1 & (d ^ rx)
+		x, y = hilbertRotate(s, x, y, rx, ry)
+		x += s * rx
+		y += s * ry
+		d /= 4
+	}
+	return x, y
+}
+
+// hilbertXY2D returns the index of a point along a Hilbert curve covering an n by n square,
+// where n is a power of two.
+func hilbertXY2D(n, x, y int) (d int) {
+	for s := n / 2; s > 0; s /= 2 {
+		rx, ry := 0, 0
+		if x&s > 0 {
+			rx = 1
+		}
+		if y&s > 0 {
+			ry = 1
+		}
+		d += s * s * ((3 * rx) ^ ry)
+		x, y = hilbertRotate(n, x, y, rx, ry)
+	}
+	return d
+}
+
+// hilbertRotate rotates and flips a quadrant of a Hilbert curve.
+func hilbertRotate(n, x, y, rx, ry int) (int, int) {
+	if ry == 0 {
+		if rx == 1 {
+			x = n - 1 - x
+			y = n - 1 - y
+		}
+		x, y = y, x
+	}
+	return x, y
+}
+
+var peano = curve{
+	side: func(size int) int { return powerAtLeast(3, size) },
+	d2xy: peanoD2XY,
+	xy2d: peanoXY2D,
+}
+
+// Peano traverses an image along a Peano curve, so that consecutive pixels are always
+// neighbours within square power-of-three bounds.
+func Peano(prev image.Point, bounds image.Rectangle) (image.Point, bool) {
+	return peano.traverse(prev, bounds)
+}
+
+// peanoDigits returns the number of base 3 digits of each coordinate in an n by n square.
+func peanoDigits(n int) int {
+	k := 0
+	for ; n > 1; n /= 3 {
+		k++
+	}
+	return k
+}
+
+// peanoD2XY returns the point at index d along a Peano curve covering an n by n square,
+// where n is a power of three. The base 3 digits of d alternate between digits of x and y,
+// which are reflected according to the parity of the preceding digits of the other coordinate.
+func peanoD2XY(n, d int) (x, y int) {
+	k := peanoDigits(n)
+	digits := make([]int, 2*k)
+	for i := 2*k - 1; i >= 0; i-- {
+		digits[i] = d % 3
+		d /= 3
+	}
+	sumA, sumB := 0, 0
+	for i := 0; i < k; i++ {
+		a, b := digits[2*i], digits[2*i+1]
+		xi := a
+		if sumB%2 == 1 {
+			xi = 2 - a
+		}
+		sumA += a
+		yi := b
+		if sumA%2 == 1 {
+			yi = 2 - b
+		}
+		sumB += b
+		x = 3*x + xi
+		y = 3*y + yi
+	}
+	return x, y
+}
+
+// peanoXY2D returns the index of a point along a Peano curve covering an n by n square,
+// where n is a power of three.
+func peanoXY2D(n, x, y int) (d int) {
+	k := peanoDigits(n)
+	xs := make([]int, k)
+	ys := make([]int, k)
+	for i := k - 1; i >= 0; i-- {
+		xs[i] = x % 3
+		ys[i] = y % 3
+		x /= 3
+		y /= 3
+	}
+	sumA, sumB := 0, 0
+	for i := 0; i < k; i++ {
+		a := xs[i]
+		if sumB%2 == 1 {
+			a = 2 - a
+		}
+		sumA += a
+		b := ys[i]
+		if sumA%2 == 1 {
+			b = 2 - b
+		}
+		sumB += b
+		d = 9*d + 3*a + b
+	}
+	return d
+}
+
+var zOrder = curve{
+	side: func(size int) int { return powerAtLeast(2, size) },
+	d2xy: zOrderD2XY,
+	xy2d: zOrderXY2D,
+}
+
+// ZOrder traverses an image along a Z-order (Morton) curve, visiting square blocks of
+// pixels one after another.
+func ZOrder(prev image.Point, bounds image.Rectangle) (image.Point, bool) {
+	return zOrder.traverse(prev, bounds)
+}
+
+// zOrderD2XY returns the point at index d along a Z-order curve, whose bits interleave
+// the bits of x and y.
+func zOrderD2XY(n, d int) (x, y int) {
+	for bit := 0; d > 0; bit++ {
+		x |= (d & 1) << bit
+		y |= ((d >> 1) & 1) << bit
+		d >>= 2
+	}
+	return x, y
+}
+
+// zOrderXY2D returns the index of a point along a Z-order curve.
+func zOrderXY2D(n, x, y int) (d int) {
+	for bit := 0; x > 0 || y > 0; bit++ {
+		d |= (x & 1) << (2 * bit)
+		d |= (y & 1) << (2*bit + 1)
+		x >>= 1
+		y >>= 1
+	}
+	return d
+}
@@ -0,0 +1,48 @@
+package traversal
+
+import (
+	"image"
+	"testing"
+)
+
+// TestCurves checks that every curve visits every pixel of bounds of many shapes exactly once.
+func TestCurves(t *testing.T) {
+	for _, name := range []string{"Hilbert", "Peano", "ZOrder"} {
+		for w := 1; w <= 12; w++ {
+			for h := 1; h <= 12; h++ {
+				b := image.Rect(3, -2, w+3, h-2)
+				tr, err := New(name, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				tr.Reset(b, nil, b.Min)
+				seen := map[image.Point]bool{}
+				for p, ok := tr.Next(); ok; p, ok = tr.Next() {
+					if !p.In(b) || seen[p] {
+						t.Fatalf("%s: traversal of %v visits %v, outside bounds or repeated", name, b, p)
+					}
+					seen[p] = true
+				}
+				if len(seen) != w*h {
+					t.Fatalf("%s: traversal of %v visits %d pixels, want %d", name, b, len(seen), w*h)
+				}
+			}
+		}
+	}
+}
+
+// TestCurvesEmpty checks that curves visit no pixels when the region traversed is empty.
+func TestCurvesEmpty(t *testing.T) {
+	im := image.NewGray(image.Rect(0, 0, 10, 10))
+	for _, name := range []string{"Hilbert", "Peano", "ZOrder"} {
+		tr, err := New(name, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr = Sampling{ROI: image.Rect(50, 50, 60, 60)}.Wrap(tr)
+		tr.Reset(im.Bounds(), im, image.Point{})
+		if p, ok := tr.Next(); ok {
+			t.Errorf("%s: traversal of an ROI outside the image visits %v", name, p)
+		}
+	}
+}
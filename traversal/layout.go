@@ -0,0 +1,76 @@
+package traversal
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// Layout splits an image into parts, each traversed by its own cursor.
+type Layout int
+
+const (
+	Rows      Layout = iota // Horizontal bands from top to bottom
+	Columns                 // Vertical bands from left to right
+	Quadrants               // The four quadrants, clockwise from the top left
+)
+
+// layoutNames are the names of Layouts, by Layout.
+var layoutNames = []string{"rows", "columns", "quadrants"}
+
+// String returns the name of the Layout.
+func (l Layout) String() string {
+	if l < 0 || int(l) >= len(layoutNames) {
+		return fmt.Sprintf("Layout(%d)", int(l))
+	}
+	return layoutNames[l]
+}
+
+// ParseLayout returns the Layout with the provided name.
+func ParseLayout(s string) (Layout, error) {
+	for i, name := range layoutNames {
+		if strings.EqualFold(s, name) {
+			return Layout(i), nil
+		}
+	}
+	return Rows, fmt.Errorf("no layout named %s", s)
+}
+
+// Split splits bounds into n parts, which is ignored for Quadrants. Rows and Columns are split
+// into one part per row or column of pixels if n isn't positive. Parts that would be empty are
+// left out.
+func (l Layout) Split(bounds image.Rectangle, n int) []image.Rectangle {
+	var parts []image.Rectangle
+	switch l {
+	case Rows, Columns:
+		size := bounds.Dy()
+		if l == Columns {
+			size = bounds.Dx()
+		}
+		if n <= 0 || n > size {
+			n = size
+		}
+		for i := 0; i < n; i++ {
+			// Spread the remainder of uneven splits across the parts
+			from, to := i*size/n, (i+1)*size/n
+			if l == Rows {
+				parts = append(parts, image.Rect(bounds.Min.X, bounds.Min.Y+from, bounds.Max.X, bounds.Min.Y+to))
+			} else {
+				parts = append(parts, image.Rect(bounds.Min.X+from, bounds.Min.Y, bounds.Min.X+to, bounds.Max.Y))
+			}
+		}
+	case Quadrants:
+		mid := image.Point{(bounds.Min.X + bounds.Max.X) / 2, (bounds.Min.Y + bounds.Max.Y) / 2}
+		for _, r := range []image.Rectangle{
+			image.Rect(bounds.Min.X, bounds.Min.Y, mid.X, mid.Y),
+			image.Rect(mid.X, bounds.Min.Y, bounds.Max.X, mid.Y),
+			image.Rect(mid.X, mid.Y, bounds.Max.X, bounds.Max.Y),
+			image.Rect(bounds.Min.X, mid.Y, mid.X, bounds.Max.Y),
+		} {
+			if !r.Empty() {
+				parts = append(parts, r)
+			}
+		}
+	}
+	return parts
+}
@@ -0,0 +1,202 @@
+package traversal
+
+import (
+	"image"
+	"math"
+
+	"github.com/rytrose/pixelsound/api"
+)
+
+// order is an order of the pixels within bounds, numbering them from 0.
+type order struct {
+	index func(p image.Point, bounds image.Rectangle) int // Returns the number of a pixel within bounds
+	point func(i int, bounds image.Rectangle) image.Point // Returns the pixel numbered i
+}
+
+// forward returns a TraverseFunc visiting pixels in order, returning the last pixel with ok
+// set to false. A traversal from a pixel outside bounds starts at the first pixel, and one
+// from the last pixel returns it again with ok set to false, as there is no pixel after it.
+func (o order) forward() api.TraverseFunc {
+	return func(prev image.Point, bounds image.Rectangle) (image.Point, bool) {
+		n := bounds.Dx() * bounds.Dy()
+		i := 0
+		if prev.In(bounds) {
+			i = o.index(prev, bounds) + 1
+		}
+		if i >= n {
+			return prev, false
+		}
+		return o.point(i, bounds), i < n-1
+	}
+}
+
+// orderTraverser is a Traverser visiting every pixel once in an order, or in its reverse.
+type orderTraverser struct {
+	o       order
+	reverse bool
+
+	bounds image.Rectangle
+	i      int // Number of the next pixel, out of range once finished
+}
+
+// newOrder returns a constructor for a traversal of pixels in an order, or in its reverse.
+func newOrder(o order, reverse bool) func(api.Params) (api.Traverser, error) {
+	return func(api.Params) (api.Traverser, error) {
+		return &orderTraverser{o: o, reverse: reverse}, nil
+	}
+}
+
+// Reset starts a new traversal from start, or from the first pixel of the order if start is
+// outside bounds. A reverse traversal from the first pixel of the order, such as the default
+// top left pixel, starts at the last pixel instead, so that it visits every pixel once.
+func (t *orderTraverser) Reset(bounds image.Rectangle, img image.Image, start image.Point) {
+	t.bounds = bounds
+	switch {
+	case !t.reverse && start.In(bounds):
+		t.i = t.o.index(start, bounds)
+	case !t.reverse:
+		t.i = 0
+	case start.In(bounds) && t.o.index(start, bounds) > 0:
+		t.i = t.o.index(start, bounds)
+	default:
+		t.i = bounds.Dx()*bounds.Dy() - 1
+	}
+}
+
+// Next returns the next pixel of the order.
+func (t *orderTraverser) Next() (image.Point, bool) {
+	if t.i < 0 || t.i >= t.bounds.Dx()*t.bounds.Dy() {
+		return image.Point{}, false
+	}
+	p := t.o.point(t.i, t.bounds)
+	if t.reverse {
+		t.i--
+	} else {
+		t.i++
+	}
+	return p, true
+}
+
+// rows is the order of rows from top to bottom, each from left to right.
+var rows = order{
+	index: func(p image.Point, b image.Rectangle) int {
+		return (p.Y-b.Min.Y)*b.Dx() + p.X - b.Min.X
+	},
+	point: func(i int, b image.Rectangle) image.Point {
+		return image.Point{b.Min.X + i%b.Dx(), b.Min.Y + i/b.Dx()}
+	},
+}
+
+// serpentine is the order of rows from top to bottom, alternating between left to right and
+// right to left.
+var serpentine = order{
+	index: func(p image.Point, b image.Rectangle) int {
+		row, col := p.Y-b.Min.Y, p.X-b.Min.X
+		if row%2 == 1 {
+			col = b.Dx() - 1 - col
+		}
+		return row*b.Dx() + col
+	},
+	point: func(i int, b image.Rectangle) image.Point {
+		row, col := i/b.Dx(), i%b.Dx()
+		if row%2 == 1 {
+			col = b.Dx() - 1 - col
+		}
+		return image.Point{b.Min.X + col, b.Min.Y + row}
+	},
+}
+
+// columns is the order of columns from left to right, each from top to bottom.
+var columns = order{
+	index: func(p image.Point, b image.Rectangle) int {
+		return (p.X-b.Min.X)*b.Dy() + p.Y - b.Min.Y
+	},
+	point: func(i int, b image.Rectangle) image.Point {
+		return image.Point{b.Min.X + i/b.Dy(), b.Min.Y + i%b.Dy()}
+	},
+}
+
+// diagonals is the order of anti-diagonals from the top left corner to the bottom right corner,
+// each from top right to bottom left, like a wavefront.
+var diagonals = order{
+	index: func(p image.Point, b image.Rectangle) int {
+		x, y := p.X-b.Min.X, p.Y-b.Min.Y
+		d := x + y
+		return diagonalStart(d, b) + y - diagonalTop(d, b)
+	},
+	point: func(i int, b image.Rectangle) image.Point {
+		w, h := b.Dx(), b.Dy()
+		short, long := w, h
+		if short > long {
+			short, long = long, short
+		}
+		// Diagonals grow up to the short side, keep its length up to the long side, then shrink
+		var d int
+		switch n := w * h; {
+		case i < triangle(short):
+			d = triangleRoot(i)
+		case i < triangle(short)+(long-short)*short:
+			d = short + (i-triangle(short))/short
+		default:
+			d = w + h - 2 - triangleRoot(n-1-i)
+		}
+		y := diagonalTop(d, b) + i - diagonalStart(d, b)
+		return image.Point{b.Min.X + d - y, b.Min.Y + y}
+	},
+}
+
+// diagonalTop returns the row of the top pixel of the anti-diagonal d pixels from the top left corner.
+func diagonalTop(d int, b image.Rectangle) int {
+	if d < b.Dx() {
+		return 0
+	}
+	return d - b.Dx() + 1
+}
+
+// diagonalStart returns the number of pixels of the anti-diagonals before the one d pixels
+// from the top left corner.
+func diagonalStart(d int, b image.Rectangle) int {
+	w, h := b.Dx(), b.Dy()
+	short, long := w, h
+	if short > long {
+		short, long = long, short
+	}
+	switch {
+	case d < short:
+		return triangle(d)
+	case d < long:
+		return triangle(short) + (d-short)*short
+	default:
+		return w*h - triangle(w+h-1-d)
+	}
+}
+
+// triangle returns the number of pixels of the first n anti-diagonals from a corner, 1+2+...+n.
+func triangle(n int) int {
+	return n * (n + 1) / 2
+}
+
+// triangleRoot returns the anti-diagonal of a corner containing the pixel numbered i from
+// the corner, the largest d such that triangle(d) <= i.
+func triangleRoot(i int) int {
+	d := int((math.Sqrt(float64(8*i+1)) - 1) / 2)
+	// Correct rounding errors of large numbers
+	for triangle(d) > i {
+		d--
+	}
+	for triangle(d+1) <= i {
+		d++
+	}
+	return d
+}
+
+var (
+	// TtoBLtoR traverses an image top-to-bottom left-to-right.
+	TtoBLtoR = rows.forward()
+	// Serpentine traverses an image top-to-bottom, alternating left-to-right and right-to-left.
+	Serpentine = serpentine.forward()
+	// LtoRTtoB traverses an image left-to-right top-to-bottom, column by column.
+	LtoRTtoB = columns.forward()
+	// Diagonal traverses an image along anti-diagonals from the top left to the bottom right.
+	Diagonal = diagonals.forward()
+)
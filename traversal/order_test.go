@@ -0,0 +1,166 @@
+package traversal
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/rytrose/pixelsound/api"
+)
+
+// pts returns the points of pairs of coordinates.
+func pts(coords ...int) []image.Point {
+	points := make([]image.Point, 0, len(coords)/2)
+	for i := 0; i+1 < len(coords); i += 2 {
+		points = append(points, image.Point{coords[i], coords[i+1]})
+	}
+	return points
+}
+
+func TestOrders(t *testing.T) {
+	wide, tall, row := image.Rect(0, 0, 3, 2), image.Rect(0, 0, 1, 3), image.Rect(0, 0, 3, 1)
+	tests := []struct {
+		name   string
+		bounds image.Rectangle
+		start  image.Point
+		want   []image.Point
+	}{
+		{"TtoBLtoR", wide, image.Point{}, pts(0, 0, 1, 0, 2, 0, 0, 1, 1, 1, 2, 1)},
+		{"Serpentine", wide, image.Point{}, pts(0, 0, 1, 0, 2, 0, 2, 1, 1, 1, 0, 1)},
+		{"SerpentineReverse", wide, image.Point{}, pts(0, 1, 1, 1, 2, 1, 2, 0, 1, 0, 0, 0)},
+		{"LtoRTtoB", wide, image.Point{}, pts(0, 0, 0, 1, 1, 0, 1, 1, 2, 0, 2, 1)},
+		{"LtoRTtoBReverse", wide, image.Point{}, pts(2, 1, 2, 0, 1, 1, 1, 0, 0, 1, 0, 0)},
+		{"Diagonal", wide, image.Point{}, pts(0, 0, 1, 0, 0, 1, 2, 0, 1, 1, 2, 1)},
+		{"DiagonalReverse", wide, image.Point{}, pts(2, 1, 1, 1, 2, 0, 0, 1, 1, 0, 0, 0)},
+
+		{"TtoBLtoR", tall, image.Point{}, pts(0, 0, 0, 1, 0, 2)},
+		{"Serpentine", tall, image.Point{}, pts(0, 0, 0, 1, 0, 2)},
+		{"SerpentineReverse", tall, image.Point{}, pts(0, 2, 0, 1, 0, 0)},
+		{"LtoRTtoB", tall, image.Point{}, pts(0, 0, 0, 1, 0, 2)},
+		{"LtoRTtoBReverse", tall, image.Point{}, pts(0, 2, 0, 1, 0, 0)},
+		{"Diagonal", tall, image.Point{}, pts(0, 0, 0, 1, 0, 2)},
+		{"DiagonalReverse", tall, image.Point{}, pts(0, 2, 0, 1, 0, 0)},
+
+		{"TtoBLtoR", row, image.Point{}, pts(0, 0, 1, 0, 2, 0)},
+		{"Serpentine", row, image.Point{}, pts(0, 0, 1, 0, 2, 0)},
+		{"SerpentineReverse", row, image.Point{}, pts(2, 0, 1, 0, 0, 0)},
+		{"LtoRTtoB", row, image.Point{}, pts(0, 0, 1, 0, 2, 0)},
+		{"LtoRTtoBReverse", row, image.Point{}, pts(2, 0, 1, 0, 0, 0)},
+		{"Diagonal", row, image.Point{}, pts(0, 0, 1, 0, 2, 0)},
+		{"DiagonalReverse", row, image.Point{}, pts(2, 0, 1, 0, 0, 0)},
+
+		// Traversals from a pixel within bounds start there
+		{"Diagonal", wide, image.Point{2, 0}, pts(2, 0, 1, 1, 2, 1)},
+		{"DiagonalReverse", wide, image.Point{2, 0}, pts(2, 0, 0, 1, 1, 0, 0, 0)},
+		// Traversals from a pixel outside bounds start at the beginning of their order
+		{"TtoBLtoR", image.Rect(5, 5, 7, 6), image.Point{}, pts(5, 5, 6, 5)},
+		{"SerpentineReverse", image.Rect(5, 5, 7, 6), image.Point{}, pts(6, 5, 5, 5)},
+		{"DiagonalReverse", image.Rect(0, 0, 1, 1), image.Point{}, pts(0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := New(tt.name, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tr.Reset(tt.bounds, nil, tt.start)
+			var got []image.Point
+			for p, ok := tr.Next(); ok; p, ok = tr.Next() {
+				got = append(got, p)
+				if len(got) > len(tt.want) {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("traversal of %v from %v is %v, want %v", tt.bounds, tt.start, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestOrderIndex checks that numbering the pixels of every order is the inverse of finding
+// the pixel of a number, for bounds of many shapes.
+func TestOrderIndex(t *testing.T) {
+	orders := map[string]order{"rows": rows, "serpentine": serpentine, "columns": columns, "diagonals": diagonals}
+	for name, o := range orders {
+		for w := 1; w <= 9; w++ {
+			for h := 1; h <= 9; h++ {
+				b := image.Rect(-2, 3, w-2, h+3)
+				seen := map[image.Point]bool{}
+				for i := 0; i < w*h; i++ {
+					p := o.point(i, b)
+					if !p.In(b) || seen[p] {
+						t.Fatalf("%s: pixel %d of %v is %v, outside bounds or repeated", name, i, b, p)
+					}
+					seen[p] = true
+					if got := o.index(p, b); got != i {
+						t.Fatalf("%s: index of %v in %v is %d, want %d", name, p, b, got, i)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestOrderFuncs checks that the TraverseFuncs of orders, adapted to Traversers, visit every
+// pixel once, from the first pixel or from any other.
+func TestOrderFuncs(t *testing.T) {
+	funcs := map[string]api.TraverseFunc{"TtoBLtoR": TtoBLtoR, "Serpentine": Serpentine, "LtoRTtoB": LtoRTtoB, "Diagonal": Diagonal}
+	tests := []struct {
+		name   string
+		bounds image.Rectangle
+		start  image.Point
+		want   []image.Point
+	}{
+		{"Serpentine", image.Rect(0, 0, 2, 2), image.Point{}, pts(0, 0, 1, 0, 1, 1, 0, 1)},
+		{"LtoRTtoB", image.Rect(0, 0, 2, 2), image.Point{0, 1}, pts(0, 1, 1, 0, 1, 1)},
+		{"TtoBLtoR", image.Rect(0, 0, 1, 1), image.Point{}, pts(0, 0)},
+		{"Diagonal", image.Rect(0, 0, 1, 1), image.Point{}, pts(0, 0)},
+		{"TtoBLtoR", image.Rect(0, 0, 2, 2), image.Point{1, 1}, pts(1, 1)},
+		{"Serpentine", image.Rect(0, 0, 2, 2), image.Point{0, 1}, pts(0, 1)},
+		{"Diagonal", image.Rect(3, 3, 3, 3), image.Point{3, 3}, nil},
+		{"TtoBLtoR", image.Rect(5, 5, 7, 6), image.Point{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := api.NewFuncTraverser(funcs[tt.name])
+			tr.Reset(tt.bounds, nil, tt.start)
+			var got []image.Point
+			for p, ok := tr.Next(); ok; p, ok = tr.Next() {
+				got = append(got, p)
+				if len(got) > len(tt.want) {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("traversal of %v from %v is %v, want %v", tt.bounds, tt.start, got, tt.want)
+			}
+		})
+	}
+
+	// Adapted funcs match the registered Traversers
+	for name, f := range funcs {
+		for w := 1; w <= 5; w++ {
+			for h := 1; h <= 5; h++ {
+				b := image.Rect(1, -1, w+1, h-1)
+				want, err := New(name, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				tr := api.NewFuncTraverser(f)
+				tr.Reset(b, nil, b.Min)
+				want.Reset(b, nil, b.Min)
+				for i := 0; ; i++ {
+					p, ok := tr.Next()
+					q, wantOk := want.Next()
+					if ok != wantOk || ok && p != q {
+						t.Fatalf("%s: pixel %d of %v is %v, %v, want %v, %v", name, i, b, p, ok, q, wantOk)
+					}
+					if !ok {
+						break
+					}
+				}
+			}
+		}
+	}
+}
@@ -10,9 +10,39 @@ func init() {
 	rand.Seed(time.Now().UTC().UnixNano())
 }
 
-// Random traverses an image in a random fashion.
+// Random traverses an image in a random fashion. It finishes at once if bounds are empty.
 func Random(prev image.Point, bounds image.Rectangle) (image.Point, bool) {
-	x := bounds.Min.X + rand.Intn(bounds.Max.X)
-	y := bounds.Min.Y + rand.Intn(bounds.Max.Y)
+	if bounds.Empty() {
+		return prev, false
+	}
+	x := bounds.Min.X + rand.Intn(bounds.Dx())
+	y := bounds.Min.Y + rand.Intn(bounds.Dy())
 	return image.Point{x, y}, true
 }
+
+// Shuffle is a Traverser that visits every pixel once in a random order, then finishes.
+// The order is the same every time for the same Seed and bounds.
+type Shuffle struct {
+	Seed int64
+
+	bounds image.Rectangle
+	perm   []int // Pixel indices of the current traversal in order
+	i      int   // Index of the next pixel in perm
+}
+
+// Reset starts a new shuffled traversal.
+func (s *Shuffle) Reset(bounds image.Rectangle, img image.Image, start image.Point) {
+	s.bounds = bounds
+	s.perm = rand.New(rand.NewSource(s.Seed)).Perm(bounds.Dx() * bounds.Dy())
+	s.i = 0
+}
+
+// Next returns the next pixel of the shuffled order.
+func (s *Shuffle) Next() (image.Point, bool) {
+	if s.i >= len(s.perm) {
+		return image.Point{}, false
+	}
+	idx := s.perm[s.i]
+	s.i++
+	return image.Point{s.bounds.Min.X + idx%s.bounds.Dx(), s.bounds.Min.Y + idx/s.bounds.Dx()}, true
+}
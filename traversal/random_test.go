@@ -0,0 +1,13 @@
+package traversal
+
+import (
+	"image"
+	"testing"
+)
+
+func TestRandomEmpty(t *testing.T) {
+	prev := image.Point{5, 5}
+	if p, ok := Random(prev, image.Rect(5, 5, 5, 8)); ok || p != prev {
+		t.Errorf("Random within empty bounds returned %v, %v, want %v, false", p, ok, prev)
+	}
+}
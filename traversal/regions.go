@@ -0,0 +1,111 @@
+package traversal
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/util"
+)
+
+// Regions is a Traverser that segments the image into regions of similar color and visits
+// every pixel once, region by region, flood filling each region outwards from where it begins.
+// The region of the start pixel is visited first. It emits a RegionEvent whenever it enters
+// a region.
+type Regions struct {
+	api.Emitter
+	Tolerance float64 // Maximum distance between the colors of a region and its first pixel, from 0 to 1
+
+	order   []image.Point // Pixels of the current traversal in order
+	regions []region      // Regions of the current traversal in order
+	i       int           // Index of the next pixel of the current traversal
+	r       int           // Index of the next region of the current traversal
+}
+
+// region is a region of similar color within the order of a traversal.
+type region struct {
+	start int         // Index in the order of the first pixel of the region
+	c     color.Color // Color of the first pixel of the region
+}
+
+// sides are the offsets of the 4 neighbours of a pixel that regions grow through.
+var sides = []image.Point{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+
+// Reset segments the image into regions.
+func (r *Regions) Reset(bounds image.Rectangle, img image.Image, start image.Point) {
+	w, h := bounds.Dx(), bounds.Dy()
+	r.order = nil
+	r.regions = nil
+	r.i = 0
+	r.r = 0
+	if w <= 0 || h <= 0 {
+		return
+	}
+	index := func(p image.Point) int {
+		return (p.Y-bounds.Min.Y)*w + p.X - bounds.Min.X
+	}
+
+	visited := make([]bool, w*h)
+	fill := func(seed image.Point) {
+		c := img.At(seed.X, seed.Y)
+		r.regions = append(r.regions, region{start: len(r.order), c: c})
+		visited[index(seed)] = true
+		queue := []image.Point{seed}
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			r.order = append(r.order, p)
+			for _, d := range sides {
+				n := p.Add(d)
+				if n.In(bounds) && !visited[index(n)] && colorDistance(c, img.At(n.X, n.Y)) <= r.Tolerance {
+					visited[index(n)] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+
+	if start.In(bounds) {
+		fill(start)
+	}
+	for i := range visited {
+		if !visited[i] {
+			fill(image.Point{bounds.Min.X + i%w, bounds.Min.Y + i/w})
+		}
+	}
+}
+
+// Next returns the next pixel of the current region, or the first pixel of the next region,
+// emitting a RegionEvent for it.
+func (r *Regions) Next() (image.Point, bool) {
+	if r.i >= len(r.order) {
+		return image.Point{}, false
+	}
+	for r.r < len(r.regions) && r.regions[r.r].start <= r.i {
+		r.emitRegion()
+	}
+	r.i++
+	return r.order[r.i-1], true
+}
+
+// emitRegion emits a RegionEvent for the next region.
+func (r *Regions) emitRegion() {
+	if r.r >= len(r.regions) {
+		return
+	}
+	r.Emit(api.Event{
+		Kind:   api.RegionEvent,
+		Region: r.r,
+		Color:  r.regions[r.r].c,
+	})
+	r.r++
+}
+
+// colorDistance returns the Euclidean distance between the red, green and blue of two colors,
+// from 0 to 1.
+func colorDistance(a, b color.Color) float64 {
+	ar, ag, ab, _ := util.FloatRGBA(a)
+	br, bg, bb, _ := util.FloatRGBA(b)
+	return math.Sqrt(((ar-br)*(ar-br) + (ag-bg)*(ag-bg) + (ab-bb)*(ab-bb)) / 3)
+}
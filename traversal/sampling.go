@@ -0,0 +1,145 @@
+package traversal
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/rytrose/pixelsound/api"
+)
+
+// Sampling configures how coarsely a Traverser traverses an image. The zero value traverses
+// every pixel of the image.
+type Sampling struct {
+	Stride int             // If greater than 1, only every Stride-th cell is traversed in each direction
+	Block  int             // If greater than 1, Block by Block tiles of pixels are traversed as one cell with their average color
+	ROI    image.Rectangle // If not empty, only the pixels within ROI are traversed
+}
+
+// Wrap returns a Traverser that traverses the cells of an image with t, as configured by the Sampling.
+// The points it returns are the top left pixels of cells, in image coordinates.
+func (s Sampling) Wrap(t api.Traverser) api.Traverser {
+	if s.cell() == 1 && s.ROI.Empty() {
+		return t
+	}
+	return &sampled{Sampling: s, t: t}
+}
+
+// block returns the width of the tiles of pixels averaged into one cell.
+func (s Sampling) block() int {
+	if s.Block > 1 {
+		return s.Block
+	}
+	return 1
+}
+
+// cell returns the distance between the cells that are traversed.
+func (s Sampling) cell() int {
+	if s.Stride > 1 {
+		return s.block() * s.Stride
+	}
+	return s.block()
+}
+
+// Bounds returns the part of bounds that is traversed.
+func (s Sampling) Bounds(bounds image.Rectangle) image.Rectangle {
+	if s.ROI.Empty() {
+		return bounds
+	}
+	return bounds.Intersect(s.ROI)
+}
+
+// Snap returns the top left pixel of the traversed cell nearest to p within bounds, such as
+// the cell under the mouse.
+func (s Sampling) Snap(bounds image.Rectangle, p image.Point) image.Point {
+	b := s.Bounds(bounds)
+	if b.Empty() {
+		return p
+	}
+	p.X = clamp(p.X, b.Min.X, b.Max.X-1)
+	p.Y = clamp(p.Y, b.Min.Y, b.Max.Y-1)
+	return b.Min.Add(p.Sub(b.Min).Div(s.cell()).Mul(s.cell()))
+}
+
+// ColorAt returns the average color of the tile of pixels of the cell at p.
+func (s Sampling) ColorAt(img image.Image, p image.Point) color.Color {
+	n := s.block()
+	if n == 1 {
+		return img.At(p.X, p.Y)
+	}
+	tile := image.Rect(p.X, p.Y, p.X+n, p.Y+n).Intersect(s.Bounds(img.Bounds()))
+	if tile.Empty() {
+		return img.At(p.X, p.Y)
+	}
+	var r, g, b, a uint64
+	for y := tile.Min.Y; y < tile.Max.Y; y++ {
+		for x := tile.Min.X; x < tile.Max.X; x++ {
+			cr, cg, cb, ca := img.At(x, y).RGBA()
+			r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+		}
+	}
+	count := uint64(tile.Dx() * tile.Dy())
+	return color.RGBA64{uint16(r / count), uint16(g / count), uint16(b / count), uint16(a / count)}
+}
+
+// sampled is a Traverser traversing the cells of an image with another Traverser.
+type sampled struct {
+	Sampling
+	t      api.Traverser
+	origin image.Point // Top left pixel of the first cell
+	cells  *cellImage  // Image of the cells of the current traversal
+}
+
+// Reset starts a new traversal of the cells within bounds, from the cell containing start.
+func (s *sampled) Reset(bounds image.Rectangle, img image.Image, start image.Point) {
+	b := s.Bounds(bounds)
+	c := s.cell()
+	s.origin = b.Min
+	grid := image.Rect(0, 0, (b.Dx()+c-1)/c, (b.Dy()+c-1)/c)
+	s.cells = &cellImage{img: img, s: s, origin: s.origin}
+	s.t.Reset(grid, s.cells, s.Snap(bounds, start).Sub(s.origin).Div(c))
+}
+
+// Next returns the top left pixel of the next cell.
+func (s *sampled) Next() (image.Point, bool) {
+	p, ok := s.t.Next()
+	return s.origin.Add(p.Mul(s.cell())), ok
+}
+
+// Cell returns the image of the cells of img and the cell containing the pixel at p. The image
+// is the same for every pixel of the current traversal, so that it can be cached by identity.
+func (s *sampled) Cell(img image.Image, p image.Point) (image.Image, image.Point) {
+	cells := s.cells
+	if cells == nil || cells.img != img {
+		cells = &cellImage{img: img, s: s, origin: s.Bounds(img.Bounds()).Min}
+	}
+	return cells, p.Sub(cells.origin).Div(s.cell())
+}
+
+// Subscribe subscribes to the Events of the wrapped Traverser, if it is Observable.
+func (s *sampled) Subscribe(l api.Listener) func() {
+	if o, ok := s.t.(api.Observable); ok {
+		return o.Subscribe(l)
+	}
+	return func() {}
+}
+
+// cellImage is an image with a pixel for every cell of a sampled traversal.
+type cellImage struct {
+	img    image.Image
+	s      *sampled
+	origin image.Point // Top left pixel of the first cell
+}
+
+func (im *cellImage) ColorModel() color.Model {
+	return color.RGBA64Model
+}
+
+func (im *cellImage) Bounds() image.Rectangle {
+	b := im.s.Bounds(im.img.Bounds())
+	c := im.s.cell()
+	return image.Rect(0, 0, (b.Dx()+c-1)/c, (b.Dy()+c-1)/c)
+}
+
+func (im *cellImage) At(x, y int) color.Color {
+	return im.s.ColorAt(im.img, im.origin.Add(image.Point{x, y}.Mul(im.s.cell())))
+}
@@ -0,0 +1,86 @@
+package traversal
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/faiface/beep"
+	"github.com/rytrose/pixelsound/api"
+)
+
+// cellRecorder is an ImageSonifier recording the images and pixels it sonifies.
+type cellRecorder struct {
+	bounds []image.Rectangle
+	points []image.Point
+	colors []color.Color
+}
+
+func (r *cellRecorder) Sonify(c color.Color, sr beep.SampleRate) beep.Streamer {
+	return nil
+}
+
+func (r *cellRecorder) SonifyAt(img image.Image, p image.Point, sr beep.SampleRate) beep.Streamer {
+	r.bounds = append(r.bounds, img.Bounds())
+	r.points = append(r.points, p)
+	r.colors = append(r.colors, color.RGBA64Model.Convert(img.At(p.X, p.Y)))
+	return nil
+}
+
+// grayImage returns a 4x2 image whose left half is black and right half is white.
+func grayImage() image.Image {
+	im := image.NewGray(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 2; x < 4; x++ {
+			im.SetGray(x, y, color.Gray{255})
+		}
+	}
+	return im
+}
+
+func TestSampledImageSonifier(t *testing.T) {
+	tr, err := New("TtoBLtoR", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	im := grayImage()
+	r := &cellRecorder{}
+	ps := &api.PixelSounder{Tr: Sampling{Block: 2}.Wrap(tr), S: r}
+	ps.Reset(im.Bounds(), im, image.Point{})
+	for p, ok := ps.Next(); ok; p, ok = ps.Next() {
+		api.SonifyAt(ps, im, p, 44100)
+	}
+
+	// Every block of the image is sonified as a pixel of the image of cells
+	wantPoints := []image.Point{{0, 0}, {1, 0}}
+	wantColors := []color.Color{color.RGBA64{0, 0, 0, 0xffff}, color.RGBA64{0xffff, 0xffff, 0xffff, 0xffff}}
+	if len(r.points) != len(wantPoints) {
+		t.Fatalf("sonified %v, want %v", r.points, wantPoints)
+	}
+	for i := range wantPoints {
+		if r.bounds[i] != image.Rect(0, 0, 2, 1) {
+			t.Errorf("cell %d is sonified in bounds %v, want %v", i, r.bounds[i], image.Rect(0, 0, 2, 1))
+		}
+		if r.points[i] != wantPoints[i] || r.colors[i] != wantColors[i] {
+			t.Errorf("cell %d is %v of color %v, want %v of color %v", i, r.points[i], r.colors[i], wantPoints[i], wantColors[i])
+		}
+	}
+}
+
+func TestSampledCellBeforeReset(t *testing.T) {
+	tr, err := New("TtoBLtoR", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	im := grayImage()
+	s := Sampling{Block: 2, ROI: image.Rect(2, 0, 4, 2)}.Wrap(tr).(api.CellSampler)
+
+	// A pixel played with the mouse is within the cells of the region of interest
+	cells, p := s.Cell(im, image.Point{3, 1})
+	if p != (image.Point{0, 0}) || cells.Bounds() != image.Rect(0, 0, 1, 1) {
+		t.Errorf("cell of (3,1) is %v in bounds %v, want (0,0) in bounds %v", p, cells.Bounds(), image.Rect(0, 0, 1, 1))
+	}
+	if c := color.GrayModel.Convert(cells.At(p.X, p.Y)); c != (color.Gray{255}) {
+		t.Errorf("color of cell is %v, want white", c)
+	}
+}
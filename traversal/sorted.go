@@ -0,0 +1,65 @@
+package traversal
+
+import (
+	"image"
+	"sort"
+
+	"github.com/rytrose/pixelsound/util"
+)
+
+// metricNames returns the names of the Channels of a color that pixels can be sorted by.
+func metricNames() []string {
+	var names []string
+	for _, name := range util.ChannelNames() {
+		if name != util.None.String() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Sorted is a Traverser that visits every pixel once in order of a Channel of its color, then
+// finishes. Pixels with equal values are visited top to bottom, left to right.
+type Sorted struct {
+	Metric     util.Channel // Channel of the colors of pixels that they are sorted by
+	Descending bool         // If set, visits pixels from the highest value to the lowest
+
+	order []image.Point // Pixels of the current traversal in order
+	i     int           // Index of the next pixel of the current traversal
+}
+
+// Reset sorts the pixels of the image.
+func (s *Sorted) Reset(bounds image.Rectangle, img image.Image, start image.Point) {
+	type sortedPoint struct {
+		p image.Point
+		v float64
+	}
+	points := make([]sortedPoint, 0, bounds.Dx()*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			points = append(points, sortedPoint{image.Point{x, y}, s.Metric.Value(img.At(x, y))})
+		}
+	}
+	// Points are already in position order, which a stable sort keeps for equal values
+	sort.SliceStable(points, func(i, j int) bool {
+		if s.Descending {
+			return points[i].v > points[j].v
+		}
+		return points[i].v < points[j].v
+	})
+
+	s.order = make([]image.Point, len(points))
+	for i, sp := range points {
+		s.order[i] = sp.p
+	}
+	s.i = 0
+}
+
+// Next returns the next pixel in sorted order.
+func (s *Sorted) Next() (image.Point, bool) {
+	if s.i >= len(s.order) {
+		return image.Point{}, false
+	}
+	s.i++
+	return s.order[s.i-1], true
+}
@@ -0,0 +1,120 @@
+package traversal
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// Spiral is a Traverser that visits every pixel once along a spiral, then finishes. An outward
+// spiral starts at its origin and an inward spiral ends there, wherever the traversal starts from.
+type Spiral struct {
+	Archimedean      bool // If set, follows an Archimedean spiral, otherwise a square spiral
+	Inward           bool // If set, spirals in to the origin, finishing there, otherwise out from it
+	CounterClockwise bool // If set, turns counter-clockwise, otherwise clockwise
+	FromStart        bool // If set, the origin is the pixel the traversal starts from, otherwise the center
+
+	order []image.Point // Pixels of the current traversal in order
+	i     int           // Index of the next pixel of the current traversal
+}
+
+// Reset starts a new spiral.
+func (s *Spiral) Reset(bounds image.Rectangle, img image.Image, start image.Point) {
+	origin := image.Point{(bounds.Min.X + bounds.Max.X - 1) / 2, (bounds.Min.Y + bounds.Max.Y - 1) / 2}
+	if s.FromStart && start.In(bounds) {
+		origin = start
+	}
+	if s.Archimedean {
+		s.order = archimedeanOrder(origin, bounds, s.CounterClockwise)
+	} else {
+		s.order = squareOrder(origin, bounds, s.CounterClockwise)
+	}
+	if s.Inward {
+		for l, r := 0, len(s.order)-1; l < r; l, r = l+1, r-1 {
+			s.order[l], s.order[r] = s.order[r], s.order[l]
+		}
+	}
+	s.i = 0
+}
+
+// Next returns the next pixel along the spiral.
+func (s *Spiral) Next() (image.Point, bool) {
+	if s.i >= len(s.order) {
+		return image.Point{}, false
+	}
+	s.i++
+	return s.order[s.i-1], true
+}
+
+// squareOrder returns every pixel within bounds along a square spiral out from origin.
+func squareOrder(origin image.Point, bounds image.Rectangle, counterClockwise bool) []image.Point {
+	// Image coordinates have Y increasing downwards, so right then down turns clockwise
+	dirs := []image.Point{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+	if counterClockwise {
+		dirs = []image.Point{{1, 0}, {0, -1}, {-1, 0}, {0, 1}}
+	}
+	total := bounds.Dx() * bounds.Dy()
+	if total <= 0 {
+		return nil
+	}
+
+	order := make([]image.Point, 0, total)
+	p := origin
+	if p.In(bounds) {
+		order = append(order, p)
+	}
+	// Walk legs of length 1, 1, 2, 2, 3, 3, ... until every pixel is visited
+	for leg := 0; len(order) < total; leg++ {
+		dir := dirs[leg%4]
+		for step := 0; step < leg/2+1; step++ {
+			p = p.Add(dir)
+			if p.In(bounds) {
+				order = append(order, p)
+			}
+		}
+	}
+	return order
+}
+
+// archimedeanOrder returns every pixel within bounds along an Archimedean spiral out from
+// origin, whose turns are one pixel apart.
+func archimedeanOrder(origin image.Point, bounds image.Rectangle, counterClockwise bool) []image.Point {
+	type spiralPoint struct {
+		p     image.Point
+		theta float64 // Angle along the spiral, increasing with each turn
+		r     float64 // Distance from origin
+	}
+	var points []spiralPoint
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			dx, dy := float64(x-origin.X), float64(y-origin.Y)
+			if counterClockwise {
+				dy = -dy
+			}
+			r := math.Hypot(dx, dy)
+			phi := math.Atan2(dy, dx)
+			if phi < 0 {
+				phi += 2 * math.Pi
+			}
+			// The spiral r = theta / 2π passes through the pixel on the turn nearest to r
+			turn := math.Max(0, math.Round(r-phi/(2*math.Pi)))
+			points = append(points, spiralPoint{
+				p:     image.Point{x, y},
+				theta: phi + 2*math.Pi*turn,
+				r:     r,
+			})
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		if points[i].theta != points[j].theta {
+			return points[i].theta < points[j].theta
+		}
+		return points[i].r < points[j].r
+	})
+
+	order := make([]image.Point, len(points))
+	for i, sp := range points {
+		order[i] = sp.p
+	}
+	return order
+}
@@ -1,9 +1,264 @@
 package traversal
 
-import "github.com/rytrose/pixelsound/api"
+import (
+	"fmt"
+	"sort"
+	"sync"
 
-// TraverseFuncs contains all of the traversal functions.
-var TraverseFuncs = map[string]api.TraverseFunc{
-	"Random":   Random,
-	"TtoBLtoR": TtoBLtoR,
+	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/util"
+)
+
+// Traversal describes a traversal, so that it can be listed, described
+// and configured by any UI.
+type Traversal struct {
+	Name        string
+	Description string
+	Params      api.Schema                              // Parameters accepted by New
+	New         func(api.Params) (api.Traverser, error) // Creates the traversal
+}
+
+var (
+	traversalsMu sync.RWMutex
+	traversals   = map[string]Traversal{}
+)
+
+// Register makes a traversal available by its name. Traversals outside of this package
+// can be registered from an init function. Panics if the name is already registered.
+func Register(t Traversal) {
+	traversalsMu.Lock()
+	defer traversalsMu.Unlock()
+	if _, ok := traversals[t.Name]; ok {
+		panic(fmt.Sprintf("traversal %s registered twice", t.Name))
+	}
+	traversals[t.Name] = t
+}
+
+// Lookup returns the registered traversal with the provided name.
+func Lookup(name string) (Traversal, bool) {
+	traversalsMu.RLock()
+	defer traversalsMu.RUnlock()
+	t, ok := traversals[name]
+	return t, ok
+}
+
+// Names returns the sorted names of all registered traversals.
+func Names() []string {
+	traversalsMu.RLock()
+	defer traversalsMu.RUnlock()
+	names := make([]string, 0, len(traversals))
+	for name := range traversals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the registered traversal with the provided name, configured by
+// parameter values parsed from strings.
+func New(name string, values map[string]string) (api.Traverser, error) {
+	t, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("no traversal function named %s", name)
+	}
+	params, err := t.Params.Parse(values)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return t.New(params)
+}
+
+// newFunc returns a constructor for a traversal function without parameters.
+func newFunc(f api.TraverseFunc) func(api.Params) (api.Traverser, error) {
+	return func(api.Params) (api.Traverser, error) {
+		return api.NewFuncTraverser(f), nil
+	}
+}
+
+func init() {
+	Register(Traversal{
+		Name:        "Random",
+		Description: "Jumps to a random pixel every step, forever.",
+		New:         newFunc(Random),
+	})
+	Register(Traversal{
+		Name:        "Regions",
+		Description: "Visits every pixel once, region by region, flood filling each region of similar color.",
+		Params: api.Schema{
+			{
+				Name:        "tolerance",
+				Description: "maximum difference between the colors of a region",
+				Kind:        api.FloatParam,
+				Default:     0.1,
+				Min:         0,
+				Max:         1,
+			},
+		},
+		New: func(p api.Params) (api.Traverser, error) {
+			return &Regions{Tolerance: p.Float("tolerance")}, nil
+		},
+	})
+	Register(Traversal{
+		Name:        "Shuffle",
+		Description: "Visits every pixel once in a random order, which is the same for the same seed.",
+		Params: api.Schema{
+			{
+				Name:        "seed",
+				Description: "seed of the random order",
+				Kind:        api.IntParam,
+				Default:     1,
+			},
+		},
+		New: func(p api.Params) (api.Traverser, error) {
+			return &Shuffle{Seed: int64(p.Int("seed"))}, nil
+		},
+	})
+	Register(Traversal{
+		Name:        "TtoBLtoR",
+		Description: "Scans rows from top to bottom, each from left to right.",
+		New:         newOrder(rows, false),
+	})
+	Register(Traversal{
+		Name:        "Contour",
+		Description: "Walks along the edges detected in the image, jumping to the next edge when one ends.",
+		Params: api.Schema{
+			{
+				Name:        "threshold",
+				Description: "minimum strength of an edge, relative to the strongest edge",
+				Kind:        api.FloatParam,
+				Default:     0.25,
+				Min:         0,
+				Max:         1,
+			},
+			{
+				Name:        "thin",
+				Description: "whether to thin edges to single pixel lines",
+				Kind:        api.BoolParam,
+				Default:     true,
+			},
+		},
+		New: func(p api.Params) (api.Traverser, error) {
+			return &Contour{
+				Threshold: p.Float("threshold"),
+				Thin:      p.Bool("thin"),
+			}, nil
+		},
+	})
+	Register(Traversal{
+		Name:        "Serpentine",
+		Description: "Scans rows from top to bottom, alternating left to right and right to left.",
+		New:         newOrder(serpentine, false),
+	})
+	Register(Traversal{
+		Name:        "SerpentineReverse",
+		Description: "Scans rows from bottom to top, alternating left to right and right to left.",
+		New:         newOrder(serpentine, true),
+	})
+	Register(Traversal{
+		Name:        "LtoRTtoB",
+		Description: "Scans columns from left to right, each from top to bottom.",
+		New:         newOrder(columns, false),
+	})
+	Register(Traversal{
+		Name:        "LtoRTtoBReverse",
+		Description: "Scans columns from right to left, each from bottom to top.",
+		New:         newOrder(columns, true),
+	})
+	Register(Traversal{
+		Name:        "Diagonal",
+		Description: "Sweeps diagonals from the top left corner to the bottom right corner, like a wavefront.",
+		New:         newOrder(diagonals, false),
+	})
+	Register(Traversal{
+		Name:        "DiagonalReverse",
+		Description: "Sweeps diagonals from the bottom right corner to the top left corner, like a wavefront.",
+		New:         newOrder(diagonals, true),
+	})
+	Register(Traversal{
+		Name:        "Hilbert",
+		Description: "Follows a Hilbert curve, so that neighbouring pixels sound close together in time.",
+		New:         newFunc(Hilbert),
+	})
+	Register(Traversal{
+		Name:        "Peano",
+		Description: "Follows a Peano curve, so that neighbouring pixels sound close together in time.",
+		New:         newFunc(Peano),
+	})
+	Register(Traversal{
+		Name:        "Sorted",
+		Description: "Visits every pixel once in order of a value of its color, such as from darkest to brightest.",
+		Params: api.Schema{
+			{
+				Name:        "metric",
+				Description: "value of the color to sort by",
+				Kind:        api.StringParam,
+				Default:     util.Luma.String(),
+				Choices:     metricNames(),
+			},
+			{
+				Name:        "order",
+				Description: "whether to visit the lowest or highest values first",
+				Kind:        api.StringParam,
+				Default:     "ascending",
+				Choices:     []string{"ascending", "descending"},
+			},
+		},
+		New: func(p api.Params) (api.Traverser, error) {
+			m, err := util.ParseChannel(p.String("metric"))
+			if err != nil {
+				return nil, err
+			}
+			return &Sorted{
+				Metric:     m,
+				Descending: p.String("order") == "descending",
+			}, nil
+		},
+	})
+	Register(Traversal{
+		Name:        "Spiral",
+		Description: "Spirals out from or in to the center of the image, or the pixel the traversal starts from.",
+		Params: api.Schema{
+			{
+				Name:        "shape",
+				Description: "shape of the spiral",
+				Kind:        api.StringParam,
+				Default:     "square",
+				Choices:     []string{"square", "archimedean"},
+			},
+			{
+				Name:        "direction",
+				Description: "whether to spiral out from the origin or in to it, finishing there",
+				Kind:        api.StringParam,
+				Default:     "outward",
+				Choices:     []string{"outward", "inward"},
+			},
+			{
+				Name:        "rotation",
+				Description: "direction the spiral turns",
+				Kind:        api.StringParam,
+				Default:     "clockwise",
+				Choices:     []string{"clockwise", "counterclockwise"},
+			},
+			{
+				Name:        "origin",
+				Description: "center of the spiral",
+				Kind:        api.StringParam,
+				Default:     "center",
+				Choices:     []string{"center", "start"},
+			},
+		},
+		New: func(p api.Params) (api.Traverser, error) {
+			return &Spiral{
+				Archimedean:      p.String("shape") == "archimedean",
+				Inward:           p.String("direction") == "inward",
+				CounterClockwise: p.String("rotation") == "counterclockwise",
+				FromStart:        p.String("origin") == "start",
+			}, nil
+		},
+	})
+	Register(Traversal{
+		Name:        "ZOrder",
+		Description: "Follows a Z-order (Morton) curve, visiting square blocks of pixels one after another.",
+		New:         newFunc(ZOrder),
+	})
 }
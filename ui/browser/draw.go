@@ -10,11 +10,6 @@ import (
 	"github.com/rytrose/pixelsound/util"
 )
 
-// Scale of traversal relative to displayed image. Must be between 0 and 1.
-// A value of 1 means traversal will be per pixel for the displayed image.
-// Values less than 1 traverse the image in fewer steps.
-const scale = 0.1
-
 func (b *browser) resetCanvas() {
 	b.cvc.ClearRect(0, 0, float64(b.cv.Width), float64(b.cv.Height))
 }
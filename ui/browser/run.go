@@ -22,6 +22,9 @@ import (
 	"honnef.co/go/js/dom"
 )
 
+// traverseFunc is the name of the registered traversal the browser plays.
+const traverseFunc = "Random"
+
 type loadingState int32
 
 const (
@@ -47,7 +50,11 @@ type browser struct {
 	ext                 string             // Audio file extension
 	loadingState        loadingState
 	removeMouseListener func()
+	removeAudioListener func()
 	player              *player.Player
+	sr                  beep.SampleRate
+	sampling            traversal.Sampling // Fidelity of the pixels played
+	ps                  *api.PixelSounder
 }
 
 // Returns a new browser UI for running on the web.
@@ -85,13 +92,18 @@ func (b *browser) Run() {
 		return nil
 	}))
 
+	js.Global().Set("golangSetFidelity", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		go b.setFidelity(args[0].Int())
+		return nil
+	}))
+
 	js.Global().Call("jsGolangReady")
 }
 
 func (b *browser) setup() {
 	// Setup player
-	sr := beep.SampleRate(44100)
-	b.player = player.NewPlayer(sr, 2048, player.WithLatestPoint())
+	b.sr = beep.SampleRate(44100)
+	b.player = player.NewPlayer(b.sr, 2048, player.WithLatestPoint(), player.WithDeclick(5*time.Millisecond))
 	js.Global().Call("jsGolangSetup")
 }
 
@@ -106,26 +118,22 @@ func (b *browser) run() {
 	b.w.RequestAnimationFrame(b.animate)
 
 	// Start mouse-based audio
-	updateWaveform := func(progress float64) {
-		js.Global().Call("jsUpdateWaveform", progress)
-	}
 	lastTraversalPoint := image.Point{0, 0}
 	b.removeMouseListener = OnMouseMove(b.cvEl, func(p image.Point, width int, height int) {
-		// TODO add fidelity slider to "lower the resolution"
-
 		if b.getLoadingState() != loading && b.im != nil && b.r != nil {
 			// p is the location relative to the size of the canvas.
 			// width and height are the current size of the canvas.
 			// Translate the relative location to the corresponding
 			// location on the original sized image.
-			traversalPoint := image.Point{
+			// Snap the location to the blocks of the current fidelity.
+			traversalPoint := b.sampling.Snap(b.im.Bounds(), image.Point{
 				X: int(math.Floor((float64(p.X) / float64(width)) * float64(b.im.Bounds().Dx()))),
 				Y: int(math.Floor((float64(p.Y) / float64(height)) * float64(b.im.Bounds().Dy()))),
-			}
+			})
 			if (traversalPoint.X != lastTraversalPoint.X) ||
 				(traversalPoint.Y != lastTraversalPoint.Y) {
 				lastTraversalPoint = traversalPoint
-				go b.player.PlayPixel(traversalPoint, false, updateWaveform)
+				go b.player.PlayPixel(traversalPoint, false)
 			}
 		}
 	})
@@ -187,8 +195,68 @@ func (b *browser) updateAudio(dataURLString string) {
 	b.r = &bytesReaderCloser{bytes.NewReader(data)}
 	b.ext = ext
 
-	b.player.SetPixelSound(&api.PixelSounder{
-		T: traversal.Random,
-		S: sonification.NewAudioScrubber(b.r, b.ext),
-	})
+	// Create the sonification from the registry
+	scrubber, ok := sonification.Lookup("AudioScrubber")
+	if !ok {
+		log.Println("no sonification function named AudioScrubber")
+		return
+	}
+	params := scrubber.Params.Defaults()
+	params["audio"] = api.Audio{R: b.r, Ext: b.ext}
+	s, err := scrubber.New(b.sr, params)
+	if err != nil {
+		log.Println("unable to create sonification", err)
+		return
+	}
+
+	// Update the waveform with the location being scrubbed
+	if b.removeAudioListener != nil {
+		b.removeAudioListener()
+		b.removeAudioListener = nil
+	}
+	if o, ok := s.(api.Observable); ok {
+		b.removeAudioListener = o.Subscribe(b.updateWaveform)
+	}
+
+	t, err := b.newTraverser()
+	if err != nil {
+		log.Println("unable to create traversal", err)
+		return
+	}
+	b.ps = &api.PixelSounder{
+		Tr: t,
+		S:  s,
+	}
+	b.player.SetPixelSound(b.ps)
+}
+
+// newTraverser creates the traversal from the registry, traversing the image as configured by the fidelity.
+func (b *browser) newTraverser() (api.Traverser, error) {
+	t, err := traversal.New(traverseFunc, nil)
+	if err != nil {
+		return nil, err
+	}
+	return b.sampling.Wrap(t), nil
+}
+
+// setFidelity sets the size of the blocks of pixels that are played as one pixel with their
+// average color. A size of 1 plays every pixel.
+func (b *browser) setFidelity(block int) {
+	b.sampling = traversal.Sampling{Block: block}
+	if b.ps == nil {
+		return
+	}
+	t, err := b.newTraverser()
+	if err != nil {
+		log.Println("unable to create traversal", err)
+		return
+	}
+	b.ps.Tr = t
+}
+
+// updateWaveform shows the progress of the audio being scrubbed on the waveform.
+func (b *browser) updateWaveform(e api.Event) {
+	if e.Kind == api.ProgressEvent {
+		js.Global().Call("jsUpdateWaveform", e.Progress)
+	}
 }
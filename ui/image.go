@@ -2,9 +2,25 @@ package ui
 
 import (
 	"fmt"
+	"image"
 	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
 )
 
+// LoadImageFromFile loads a GIF/PNG/JPEG image given a path to a file.
+func LoadImageFromFile(path string) (image.Image, string, error) {
+	reader, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer reader.Close()
+
+	return image.Decode(reader)
+}
+
 // hexColor returns an HTML hex-representation of c. The alpha channel is dropped
 // and precision is truncated to 8 bits per channel.
 func hexColor(c color.Color) string {
@@ -1,4 +1,4 @@
-//go:build !js
+//go:build !js && !headless
 
 package thick
 
@@ -6,27 +6,28 @@ import (
 	"flag"
 	"fmt"
 	"image"
-	"os"
-	"strings"
+	"time"
 
 	"github.com/faiface/beep"
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
 	"github.com/faiface/pixel/pixelgl"
 	"github.com/nfnt/resize"
-	"github.com/rytrose/pixelsound/api"
+	"github.com/rytrose/pixelsound/config"
 	"github.com/rytrose/pixelsound/log"
 	"github.com/rytrose/pixelsound/player"
-	"github.com/rytrose/pixelsound/sonification"
-	"github.com/rytrose/pixelsound/traversal"
 	"github.com/rytrose/pixelsound/ui"
 )
 
-type thickClient struct{}
+type thickClient struct {
+	args []string // Command line args, without the program name
+}
 
-// Returns a new thick client UI for running on Mac OS.
-func NewThickClient() ui.UI {
-	return &thickClient{}
+// Returns a new thick client UI for running on Mac OS, configured by command line args.
+func NewThickClient(args []string) ui.UI {
+	return &thickClient{
+		args: args,
+	}
 }
 
 // Runs the Mac OS UI using OpenGL.
@@ -37,25 +38,32 @@ func (c *thickClient) Run() {
 // run implements the client.
 func (c *thickClient) run() {
 	// Read in command line args
-	imageFilename := flag.String("im", "images/me.png", "image to pixelsound")
-	inputAudioFilename := flag.String("audio", "audio_inputs/my_name_is_doug_dimmadome.mp3", "audio file to use for pixelsound (if needed)")
-	mouse := flag.Bool("mouse", false, "use the mouse to play pixels instead of traverse function")
-	keyboard := flag.Bool("keyboard", false, "use the keyboard to play pixels instead of traverse function")
-	queue := flag.Bool("queue", false, "all pixels moused over or key pressed to are played sequentially, as opposed to the most recent pixel only")
-	traverseFunc := flag.String("t", "TtoBLtoR", "traversal function to use")
-	sonifyFunc := flag.String("s", "SineColor", "sonification function to use")
-	flag.Parse()
+	flags := flag.NewFlagSet("play", flag.ExitOnError)
+	imageFilename := flags.String("im", "images/me.png", "image to pixelsound")
+	inputAudioFilename := flags.String("audio", "audio_inputs/my_name_is_doug_dimmadome.mp3", "audio file to use for pixelsound (if needed)")
+	mouse := flags.Bool("mouse", false, "use the mouse to play pixels instead of traverse function")
+	keyboard := flags.Bool("keyboard", false, "use the keyboard to play pixels instead of traverse function")
+	queue := flags.Bool("queue", false, "all pixels moused over or key pressed to are played sequentially, as opposed to the most recent pixel only")
+	traverseFunc := flags.String("t", "TtoBLtoR", "traversal function to use")
+	sonifyFunc := flags.String("s", "SineColor", "sonification function to use")
+	traverseParams := config.ParamValues{}
+	flags.Var(traverseParams, "tp", "traversal function parameter as name=value, may be repeated")
+	sonifyParams := config.ParamValues{}
+	flags.Var(sonifyParams, "sp", "sonification function parameter as name=value, may be repeated")
+	declick := flags.Duration("declick", 5*time.Millisecond, "duration to smooth the joins between pixels over, or 0 to disable")
+	width := flags.Uint("width", 100, "width to resize the image to, or 0 to keep the original size")
+	sampling := config.SamplingFlags(flags)
+	cursors := config.CursorFlags(flags)
+	flags.Parse(c.args)
 
 	// Load image
-	im, _, err := LoadImageFromFile(*imageFilename)
+	im, _, err := ui.LoadImageFromFile(*imageFilename)
 	if err != nil {
 		panic(fmt.Sprintf("unable to load image %s: %s", *imageFilename, err))
 	}
 
-	// Resize image to pretty small
-	im = resize.Resize(100, 0, im, resize.NearestNeighbor)
-	if err != nil {
-		panic(fmt.Sprintf("unable to resize image: %s", err))
+	if *width > 0 {
+		im = resize.Resize(*width, 0, im, resize.NearestNeighbor)
 	}
 
 	// Configure UI window
@@ -79,50 +87,22 @@ func (c *thickClient) run() {
 	// Create imdraw
 	imd := imdraw.New(nil)
 
-	// Setup necessary audio inputs
-	audioFilename := *inputAudioFilename
-	var f *os.File
-	var ext string
-	if audioFilename != "" {
-		f, err = os.Open(audioFilename)
-		if err != nil {
-			log.Fatalf("unable to open file %s: %s", audioFilename, err)
-		}
-		split := strings.Split(audioFilename, ".")
-		ext = strings.ToLower(split[len(split)-1])
-	}
-
 	// Create PixelSound player
 	sr := beep.SampleRate(44100)
-	player := player.NewPlayer(sr, 2048, player.WithPointChan())
+	player := player.NewPlayer(sr, 2048, player.WithPointChan(), player.WithLatestPoint(), player.WithDeclick(*declick))
 
-	// Instantiate and play PixelSound
-	t, ok := traversal.TraverseFuncs[*traverseFunc]
-	if !ok {
-		log.Fatalf("no traversal function named %s", *traverseFunc)
-	}
-	_, ok = sonification.SonifyFuncNames[*sonifyFunc]
-	if !ok {
-		log.Fatalf("no sonification function named %s", *sonifyFunc)
-	}
-	var s api.SonifyFunc
-	switch *sonifyFunc {
-	case "SineColor":
-		s = sonification.NewSineColor(sr)
-	case "AudioScrubber":
-		s = sonification.NewAudioScrubber(f, ext)
-	}
-	ps := &api.PixelSounder{
-		T: t,
-		S: s,
+	// Instantiate and play PixelSounds, one per cursor
+	cs, err := config.NewCursors(*traverseFunc, traverseParams, *sonifyFunc, sonifyParams, *inputAudioFilename, *sampling, *cursors, im.Bounds(), image.Point{0, 0}, sr)
+	if err != nil {
+		log.Fatal(err)
 	}
-	player.SetImagePixelSound(im, ps)
+	player.SetImagePixelSound(im, cs[0].PS)
 
 	// PLAY W/MOUSE
 	if *mouse {
 		// Register play pixel on mouse movement
 		stop := OnMouseMove(func(p pixel.Vec) {
-			player.PlayPixel(convertPixelToImage(win, p), *queue)
+			player.PlayPixel(sampling.Snap(im.Bounds(), convertPixelToImage(win, p)), *queue)
 		})
 		defer stop()
 	} else if *keyboard {
@@ -139,7 +119,7 @@ func (c *thickClient) run() {
 				newX = win.Bounds().Max.X
 			}
 			keyboardPixelLocation.X = newX
-			player.PlayPixel(convertPixelToImage(win, keyboardPixelLocation), *queue)
+			player.PlayPixel(sampling.Snap(im.Bounds(), convertPixelToImage(win, keyboardPixelLocation)), *queue)
 		}, true)
 		defer stopL()
 
@@ -150,7 +130,7 @@ func (c *thickClient) run() {
 				newX = 0
 			}
 			keyboardPixelLocation.X = newX
-			player.PlayPixel(convertPixelToImage(win, keyboardPixelLocation), *queue)
+			player.PlayPixel(sampling.Snap(im.Bounds(), convertPixelToImage(win, keyboardPixelLocation)), *queue)
 		}, true)
 		defer stopR()
 
@@ -161,7 +141,7 @@ func (c *thickClient) run() {
 				newY = 0
 			}
 			keyboardPixelLocation.Y = newY
-			player.PlayPixel(convertPixelToImage(win, keyboardPixelLocation), *queue)
+			player.PlayPixel(sampling.Snap(im.Bounds(), convertPixelToImage(win, keyboardPixelLocation)), *queue)
 		}, true)
 		defer stopU()
 
@@ -172,11 +152,11 @@ func (c *thickClient) run() {
 				newY = win.Bounds().Max.Y
 			}
 			keyboardPixelLocation.Y = newY
-			player.PlayPixel(convertPixelToImage(win, keyboardPixelLocation), *queue)
+			player.PlayPixel(sampling.Snap(im.Bounds(), convertPixelToImage(win, keyboardPixelLocation)), *queue)
 		}, true)
 		defer stopD()
 	} else { // PLAY W/TRAVERSAL
-		player.Play(im, ps, image.Point{0, 0})
+		player.PlayCursors(im, cs)
 	}
 
 	// Draw initial picture
@@ -185,8 +165,18 @@ func (c *thickClient) run() {
 	// UI main loop
 	for !win.Closed() {
 		select {
-		case point := <-player.PointChan:
-			DrawImage(win, sprite, imd, im.At(point.X, point.Y), point)
+		case <-player.PointChan:
+			// Drain the points played since the last frame, then draw the latest point of every cursor
+			for len(player.PointChan) > 0 {
+				<-player.PointChan
+			}
+			player.PointLock.Lock()
+			points := append([]image.Point{}, player.LatestPoints...)
+			if len(points) == 0 && player.LatestPoint != nil {
+				points = append(points, *player.LatestPoint)
+			}
+			player.PointLock.Unlock()
+			DrawImage(win, sprite, imd, im, points)
 		default:
 		}
 		win.Update()
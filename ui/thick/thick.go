@@ -1,10 +1,10 @@
-//go:build js
+//go:build js || headless
 
 package thick
 
 import "github.com/rytrose/pixelsound/ui"
 
-// Returns a nil thick client UI when compiling for a non-darwin OS.
-func NewThickClient() ui.UI {
+// Returns a nil thick client UI when compiling for a non-darwin OS, or without a window.
+func NewThickClient(args []string) ui.UI {
 	return nil
 }
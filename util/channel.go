@@ -0,0 +1,63 @@
+package util
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// Channel is a component of a color, such as its red or its hue.
+type Channel int
+
+const (
+	None Channel = iota // No channel, whose value is always 0
+	Red
+	Green
+	Blue
+	Alpha
+	Hue
+	Saturation
+	Lightness
+	Luma // Luminance of the red, green and blue
+)
+
+// channelNames are the names of Channels, by Channel.
+var channelNames = []string{"none", "red", "green", "blue", "alpha", "hue", "saturation", "lightness", "luminance"}
+
+// ChannelNames returns the names of the Channels, in order.
+func ChannelNames() []string {
+	return append([]string(nil), channelNames...)
+}
+
+// String returns the name of the Channel.
+func (c Channel) String() string {
+	if c < 0 || int(c) >= len(channelNames) {
+		return fmt.Sprintf("Channel(%d)", int(c))
+	}
+	return channelNames[c]
+}
+
+// ParseChannel returns the Channel with the provided name.
+func ParseChannel(s string) (Channel, error) {
+	for i, name := range channelNames {
+		if strings.EqualFold(s, name) {
+			return Channel(i), nil
+		}
+	}
+	return None, fmt.Errorf("no channel named %s", s)
+}
+
+// Value returns the value of the Channel of a color, from 0 to 1.
+func (c Channel) Value(col color.Color) float64 {
+	switch c {
+	case Red, Green, Blue, Alpha:
+		r, g, b, a := FloatRGBA(col)
+		return [...]float64{r, g, b, a}[c-Red]
+	case Hue, Saturation, Lightness:
+		h, s, l, _ := FloatHSLA(col)
+		return [...]float64{h, s, l}[c-Hue]
+	case Luma:
+		return Luminance(col)
+	}
+	return 0
+}
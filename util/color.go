@@ -1,6 +1,9 @@
 package util
 
-import "image/color"
+import (
+	"image/color"
+	"math"
+)
 
 // Uint8RGBA converts a color.Color to r, g, b, a represented as 0-255.
 func Uint8RGBA(c color.Color) (r, g, b, a uint8) {
@@ -20,3 +23,40 @@ func FloatRGBA(c color.Color) (r, g, b, a float64) {
 	a = float64(ai) / 65535
 	return
 }
+
+// FloatHSLA converts a color.Color to hue, saturation, lightness, and alpha represented as 0.0-1.0.
+func FloatHSLA(c color.Color) (h, s, l, a float64) {
+	r, g, b, a := FloatRGBA(c)
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	l = (max + min) / 2
+	d := max - min
+	if d == 0 {
+		// Achromatic
+		return 0, 0, l, a
+	}
+	if l > 0.5 {
+		s = d / (2 - max - min)
+	} else {
+		s = d / (max + min)
+	}
+	switch max {
+	case r:
+		h = (g - b) / d
+		if g < b {
+			h += 6
+		}
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h /= 6
+	return h, s, l, a
+}
+
+// Luminance returns the luma of a color.Color represented as 0.0-1.0, weighting r, g, b as in Rec. 709.
+func Luminance(c color.Color) float64 {
+	r, g, b, _ := FloatRGBA(c)
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// FFT returns the discrete Fourier transform of x, whose length must be a power of two.
+func FFT(x []complex128) []complex128 {
+	return fft(x, -1)
+}
+
+// IFFT returns the inverse discrete Fourier transform of x, whose length must be a power of two.
+func IFFT(x []complex128) []complex128 {
+	y := fft(x, 1)
+	n := complex(float64(len(y)), 0)
+	for i := range y {
+		y[i] /= n
+	}
+	return y
+}
+
+// fft is an iterative radix-2 Cooley-Tukey transform, with sign the sign of the exponent.
+func fft(x []complex128, sign float64) []complex128 {
+	n := len(x)
+	if n&(n-1) != 0 {
+		panic("util: FFT length must be a power of two")
+	}
+	y := make([]complex128, n)
+	copy(y, x)
+
+	// Bit reversal permutation
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			y[i], y[j] = y[j], y[i]
+		}
+	}
+
+	// Butterflies
+	for size := 2; size <= n; size <<= 1 {
+		w := cmplx.Exp(complex(0, sign*2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			wk := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				a, b := y[start+k], y[start+k+size/2]*wk
+				y[start+k], y[start+k+size/2] = a+b, a-b
+				wk *= w
+			}
+		}
+	}
+	return y
+}
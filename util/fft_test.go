@@ -0,0 +1,44 @@
+package util
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+// dft is the discrete Fourier transform by definition.
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	out := make([]complex128, n)
+	for k := range out {
+		for i, v := range x {
+			out[k] += v * cmplx.Rect(1, -2*math.Pi*float64(k*i)/float64(n))
+		}
+	}
+	return out
+}
+
+func TestFFT(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 1; n <= 1024; n *= 2 {
+		x := make([]complex128, n)
+		for i := range x {
+			x[i] = complex(r.Float64()*2-1, r.Float64()*2-1)
+		}
+		want := dft(x)
+		got := FFT(x)
+		for k := range want {
+			if cmplx.Abs(got[k]-want[k]) > 1e-9*float64(n) {
+				t.Fatalf("bin %d of the FFT of %d samples is %v, want %v", k, n, got[k], want[k])
+			}
+		}
+
+		// The inverse transform restores the samples
+		for i, v := range IFFT(got) {
+			if cmplx.Abs(v-x[i]) > 1e-9 {
+				t.Fatalf("sample %d of the IFFT of the FFT of %d samples is %v, want %v", i, n, v, x[i])
+			}
+		}
+	}
+}